@@ -0,0 +1,127 @@
+package gommand
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/jimmykodes/gommand/internal/shellwords"
+)
+
+// AliasSource provides user defined aliases, see Command.UserAliases
+type AliasSource interface {
+	// Aliases returns the defined aliases, mapped to their expansion
+	Aliases() (map[string]string, error)
+}
+
+// AliasMap is an AliasSource of aliases mapped to their expansion
+type AliasMap map[string]string
+
+func (m AliasMap) Aliases() (map[string]string, error) {
+	return m, nil
+}
+
+// AliasFile is an AliasSource reading aliases from the file at the given path, with one
+// alias per line, as its name and expansion separated by =. Blank lines and lines beginning
+// with # are ignored, and a missing file defines no aliases.
+//
+//	# ~/.config/mytool/aliases
+//	deploy-prod = deploy --env prod --confirm
+//	logs = server logs --follow --since 1h
+type AliasFile string
+
+func (f AliasFile) Aliases() (map[string]string, error) {
+	file, err := os.Open(string(f))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gommand: alias file: %w", err)
+	}
+	defer file.Close()
+
+	aliases := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		name, expansion, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("gommand: alias file %s:%d: expected name = expansion", f, n)
+		}
+		aliases[name] = strings.TrimSpace(expansion)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("gommand: alias file: %w", err)
+	}
+	return aliases, nil
+}
+
+// expandAliases replaces the first of args with the shell-word split expansion of the user
+// alias it names, if any, repeating until it no longer names one. Aliases never shadow
+// subcommands, and an alias can not expand to itself, directly or through other aliases.
+// The aliases are read once per execution, and kept in ctx to be listed in the help text.
+//
+// Along with the expanded args, it returns the index in args of the argument each of them
+// came from, which is the alias for the words of its expansion.
+func (c *Command) expandAliases(ctx *Context, args []string) ([]string, []int, error) {
+	origins := make([]int, len(args))
+	for i := range origins {
+		origins[i] = i
+	}
+	aliases, err := c.userAliases()
+	if err != nil {
+		return nil, nil, err
+	}
+	ctx.userAliases = aliases
+	if len(aliases) == 0 {
+		return args, origins, nil
+	}
+
+	var expanded []string
+	for len(args) > 0 {
+		expansion, ok := aliases[args[0]]
+		if !ok {
+			break
+		}
+		if slices.Contains(expanded, args[0]) {
+			return nil, nil, fmt.Errorf("gommand: alias %s expands to itself: %s", expanded[0], strings.Join(append(expanded, args[0]), " -> "))
+		}
+		words, err := shellwords.Split(expansion)
+		if err != nil {
+			return nil, nil, fmt.Errorf("gommand: alias %s: %w", args[0], err)
+		}
+		if len(words) > 0 && words[0] == args[0] {
+			return nil, nil, fmt.Errorf("gommand: alias %s refers to itself in its expansion %q", args[0], expansion)
+		}
+		expanded = append(expanded, args[0])
+		args = append(words, args[1:]...)
+		origins = append(slices.Repeat(origins[:1], len(words)), origins[1:]...)
+	}
+	return args, origins, nil
+}
+
+// userAliases returns the aliases from UserAliases that do not shadow a subcommand
+func (c *Command) userAliases() (map[string]string, error) {
+	if c.UserAliases == nil || c.parent != nil {
+		return nil, nil
+	}
+	aliases, err := c.UserAliases.Aliases()
+	if err != nil {
+		return nil, err
+	}
+	aliases = maps.Clone(aliases)
+	maps.DeleteFunc(aliases, func(name, _ string) bool {
+		_, ok := c.commands[name]
+		return ok
+	})
+	return aliases, nil
+}
@@ -0,0 +1,203 @@
+package gommand_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
+)
+
+func TestUserAliases(t *testing.T) {
+	tests := []struct {
+		name    string
+		aliases gommand.AliasMap
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "expands",
+			aliases: gommand.AliasMap{"deploy-prod": "deploy --env prod --confirm"},
+			args:    []string{"deploy-prod", "app"},
+			want:    `env=prod confirm=true args=["app"]`,
+		},
+		{
+			name:    "quoted expansion",
+			aliases: gommand.AliasMap{"dq": `deploy "my app"`},
+			args:    []string{"dq"},
+			want:    `env=dev confirm=false args=["my app"]`,
+		},
+		{
+			name:    "chained",
+			aliases: gommand.AliasMap{"dp": "deploy-prod --confirm", "deploy-prod": "deploy --env prod"},
+			args:    []string{"dp"},
+			want:    `env=prod confirm=true args=[]`,
+		},
+		{
+			name:    "only first arg",
+			aliases: gommand.AliasMap{"x": "deploy --env prod"},
+			args:    []string{"deploy", "x"},
+			want:    `env=dev confirm=false args=["x"]`,
+		},
+		{
+			name:    "subcommands are not shadowed",
+			aliases: gommand.AliasMap{"deploy": "deploy --env prod"},
+			args:    []string{"deploy"},
+			want:    `env=dev confirm=false args=[]`,
+		},
+		{
+			name:    "recursion",
+			aliases: gommand.AliasMap{"a": "b --confirm", "b": "a"},
+			args:    []string{"a"},
+			wantErr: "gommand: alias a expands to itself: a -> b -> a",
+		},
+		{
+			name:    "self reference",
+			aliases: gommand.AliasMap{"dp": "dp --confirm"},
+			args:    []string{"dp"},
+			wantErr: `gommand: alias dp refers to itself in its expansion "dp --confirm"`,
+		},
+		{
+			name:    "invalid expansion",
+			aliases: gommand.AliasMap{"bad": `deploy "unterminated`},
+			args:    []string{"bad"},
+			wantErr: "gommand: alias bad: shellwords: unterminated quote",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			root := &gommand.Command{Name: "mytool", UserAliases: tt.aliases, SilenceHelp: true, SilenceError: true}
+			root.SubCommand(&gommand.Command{
+				Name:         "deploy",
+				ArgValidator: gommand.ArgsAny(),
+				FlagSet: flags.NewFlagSet().AddFlags(
+					flags.StringFlag("env", "dev", "environment"),
+					flags.BoolFlag("confirm", false, "skip confirmation"),
+				),
+				Run: func(ctx *gommand.Context) error {
+					_, err := fmt.Fprintf(&buf, "env=%s confirm=%v args=%q", ctx.Flags().String("env"), ctx.Flags().Bool("confirm"), ctx.Args())
+					return err
+				},
+			})
+
+			err := root.ExecuteArgs(context.Background(), tt.args)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "aliases")
+		content := "# deploy shortcuts\n\ndeploy-prod = deploy --env prod --confirm\n  dq=deploy 'my app'\n"
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := gommand.AliasFile(path).Aliases()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := map[string]string{"deploy-prod": "deploy --env prod --confirm", "dq": "deploy 'my app'"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		got, err := gommand.AliasFile(filepath.Join(t.TempDir(), "missing")).Aliases()
+		if err != nil || len(got) != 0 {
+			t.Errorf("got %v, %v, want no aliases", got, err)
+		}
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "aliases")
+		if err := os.WriteFile(path, []byte("ok = deploy\ndeploy prod\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := gommand.AliasFile(path).Aliases()
+		if want := path + ":2: expected name = expansion"; err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("got %v, want error containing %q", err, want)
+		}
+	})
+
+	t.Run("read once", func(t *testing.T) {
+		src := &countingSource{aliases: gommand.AliasMap{"dp": "deploy --env prod"}}
+		root := &gommand.Command{Name: "mytool", UserAliases: src}
+		root.SubCommand(&gommand.Command{Name: "deploy", Run: func(*gommand.Context) error { return nil }})
+
+		var stdout bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"help"}, gommand.WithHelpCommand(), gommand.WithStdout(&stdout)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(stdout.String(), "dp = deploy --env prod") {
+			t.Errorf("expected help text to list the alias, got:\n%s", stdout.String())
+		}
+		if src.calls != 1 {
+			t.Errorf("expected the aliases to be read once, got %d reads", src.calls)
+		}
+	})
+
+	t.Run("read error", func(t *testing.T) {
+		src := &countingSource{err: errors.New("permission denied")}
+		root := &gommand.Command{Name: "mytool", UserAliases: src, SilenceError: true}
+		root.SubCommand(&gommand.Command{Name: "deploy", Run: func(*gommand.Context) error { return nil }})
+
+		var stderr bytes.Buffer
+		err := root.ExecuteArgs(context.Background(), []string{"deploy"}, gommand.WithStderr(&stderr))
+		if err == nil || err.Error() != "permission denied" {
+			t.Fatalf("got error %v, want permission denied", err)
+		}
+		if src.calls != 1 || strings.Contains(stderr.String(), "User Aliases:") {
+			t.Errorf("expected the aliases to be read once and left out of the help text, got %d reads and:\n%s", src.calls, stderr.String())
+		}
+	})
+
+	t.Run("listed in help", func(t *testing.T) {
+		root := &gommand.Command{
+			Name:        "mytool",
+			UserAliases: gommand.AliasMap{"dp": "deploy --env prod", "deploy": "deploy --confirm", "a": "deploy a"},
+		}
+		root.SubCommand(&gommand.Command{Name: "deploy", Run: func(*gommand.Context) error { return nil }})
+
+		var buf bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"--help"}, gommand.WithStdout(&buf)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "User Aliases:\n  a = deploy a\n  dp = deploy --env prod\n\n"; !strings.Contains(buf.String(), want) {
+			t.Errorf("expected help text to contain %q, got:\n%s", want, buf.String())
+		}
+		if got := root.HelpData().UserAliases; got != nil {
+			t.Errorf("expected HelpData to leave out the aliases, got %v", got)
+		}
+	})
+}
+
+type countingSource struct {
+	aliases gommand.AliasMap
+	err     error
+	calls   int
+}
+
+func (s *countingSource) Aliases() (map[string]string, error) {
+	s.calls++
+	return s.aliases, s.err
+}
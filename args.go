@@ -1,6 +1,19 @@
 package gommand
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jimmykodes/gommand/flags"
+)
 
 type ArgValidator func(s []string) error
 
@@ -65,7 +78,7 @@ func ArgsSome(validators ...ArgValidator) ArgValidator {
 				errs = append(errs, err)
 			}
 		}
-		return fmt.Errorf("no validators passed: %v", errs)
+		return argErrors{msg: "no validators passed", errs: errs}
 	}
 }
 
@@ -74,3 +87,301 @@ func ArgsAny() ArgValidator {
 		return nil
 	}
 }
+
+// ArgsOneOf validates each arg is one of values
+func ArgsOneOf(values ...string) ArgValidator {
+	return argsEach(func(arg string) error {
+		if !slices.Contains(values, arg) {
+			return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+		}
+		return nil
+	})
+}
+
+// ArgsMatch validates each arg matches re
+func ArgsMatch(re *regexp.Regexp) ArgValidator {
+	return argsEach(func(arg string) error {
+		if !re.MatchString(arg) {
+			return fmt.Errorf("must match %s", re)
+		}
+		return nil
+	})
+}
+
+// ArgsFile validates each arg is the path of an existing file that is not a directory
+func ArgsFile() ArgValidator {
+	return argsEach(func(arg string) error {
+		info, err := os.Stat(arg)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			return errors.New("file does not exist")
+		case err != nil:
+			return err
+		case info.IsDir():
+			return errors.New("is a directory")
+		}
+		return nil
+	})
+}
+
+// ArgsDir validates each arg is the path of an existing directory
+func ArgsDir() ArgValidator {
+	return argsEach(func(arg string) error {
+		info, err := os.Stat(arg)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			return errors.New("directory does not exist")
+		case err != nil:
+			return err
+		case !info.IsDir():
+			return errors.New("not a directory")
+		}
+		return nil
+	})
+}
+
+// ArgsInt validates each arg is a base 10 integer
+func ArgsInt() ArgValidator {
+	return argsEach(func(arg string) error {
+		if _, err := strconv.Atoi(arg); err != nil {
+			return errors.New("not an integer")
+		}
+		return nil
+	})
+}
+
+// ArgsDuration validates each arg is a duration accepted by time.ParseDuration. ie: 1h30m
+func ArgsDuration() ArgValidator {
+	return argsEach(func(arg string) error {
+		if _, err := time.ParseDuration(arg); err != nil {
+			return errors.New("not a duration")
+		}
+		return nil
+	})
+}
+
+// ArgsURL validates each arg is an absolute URL. ie: https://example.com/path
+func ArgsURL() ArgValidator {
+	return argsEach(func(arg string) error {
+		u, err := url.Parse(arg)
+		if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+			return errors.New("not an absolute URL")
+		}
+		return nil
+	})
+}
+
+// ArgsUnique validates no arg is passed more than once
+func ArgsUnique() ArgValidator {
+	return func(s []string) error {
+		var (
+			errs []error
+			seen = make(map[string]bool, len(s))
+		)
+		for i, arg := range s {
+			if seen[arg] {
+				errs = append(errs, ErrArg{Index: i, Value: arg, Err: errors.New("already passed")})
+				continue
+			}
+			seen[arg] = true
+		}
+		return joinArgErrors(errs)
+	}
+}
+
+// ArgsAt runs validators against the arg at idx, if it was passed. A negative idx is an error.
+//
+// ex:
+// gommand.ArgsEvery(gommand.ArgsExact(2), gommand.ArgsAt(0, gommand.ArgsOneOf("get", "set")), gommand.ArgsAt(1, gommand.ArgsInt()))
+func ArgsAt(idx int, validators ...ArgValidator) ArgValidator {
+	return func(s []string) error {
+		if idx < 0 {
+			return fmt.Errorf("invalid arg index %d", idx)
+		}
+		if idx >= len(s) {
+			return nil
+		}
+		return argsFrom(s, idx, idx+1, validators)
+	}
+}
+
+// ArgsRest runs validators against the args from idx on, if any were passed. A negative idx is an error.
+func ArgsRest(idx int, validators ...ArgValidator) ArgValidator {
+	return func(s []string) error {
+		if idx < 0 {
+			return fmt.Errorf("invalid arg index %d", idx)
+		}
+		if idx >= len(s) {
+			return nil
+		}
+		return argsFrom(s, idx, len(s), validators)
+	}
+}
+
+// argsFrom runs validators against s[idx:end], shifting the index of the ErrArgs they return
+// to match s. Errors that do not name an arg are wrapped in an ErrArg for the arg at idx.
+func argsFrom(s []string, idx, end int, validators []ArgValidator) error {
+	err := ArgsEvery(validators...)(s[idx:end])
+	if err == nil {
+		return nil
+	}
+	if !errors.As(err, new(ErrArg)) {
+		return ErrArg{Index: idx, Value: s[idx], Err: err}
+	}
+	return offsetArgErrors(err, idx)
+}
+
+// argsEach returns an ArgValidator running fn against each arg, reporting every arg it rejects
+func argsEach(fn func(arg string) error) ArgValidator {
+	return func(s []string) error {
+		var errs []error
+		for i, arg := range s {
+			if err := fn(arg); err != nil {
+				errs = append(errs, ErrArg{Index: i, Value: arg, Err: err})
+			}
+		}
+		return joinArgErrors(errs)
+	}
+}
+
+// argErrors is returned by validators rejecting the args for more than one reason
+type argErrors struct {
+	msg  string
+	errs []error
+}
+
+func (e argErrors) Error() string {
+	msgs := make([]string, len(e.errs))
+	for i, err := range e.errs {
+		msgs[i] = err.Error()
+	}
+	if e.msg == "" {
+		return strings.Join(msgs, "; ")
+	}
+	return e.msg + ": " + strings.Join(msgs, "; ")
+}
+
+func (e argErrors) Unwrap() []error {
+	return e.errs
+}
+
+func joinArgErrors(errs []error) error {
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return argErrors{errs: errs}
+}
+
+// offsetArgErrors shifts the index of each ErrArg in err by off, for validators run against
+// a subslice of the args. ErrArgs are found in the errors returned by the validators of this
+// package. Errors wrapping an ErrArg in other ways, such as with fmt.Errorf or errors.Join,
+// can not be rebuilt, so the index of their ErrArg is left relative to the subslice.
+func offsetArgErrors(err error, off int) error {
+	switch e := err.(type) {
+	case ErrArg:
+		e.Index += off
+		return e
+	case argErrors:
+		errs := make([]error, len(e.errs))
+		for i, err := range e.errs {
+			errs[i] = offsetArgErrors(err, off)
+		}
+		return argErrors{msg: e.msg, errs: errs}
+	}
+	return err
+}
+
+// Arg declares a named positional arg of a command, see Command.Args
+//
+// ex:
+// gommand.Arg{Flag: flags.IntFlag("replicas", 1, "number of replicas").AddSources(flags.Environ), Optional: true}
+type Arg struct {
+	// Flag parses and holds the value of the arg, so the arg has the name, type, default value,
+	// sources and usage of the flag. It is never matched against the flags on the command line.
+	Flag flags.Flag
+
+	// Optional args can be omitted, in which case their value is read from the sources of
+	// the Flag, falling back to its default. Only the last args of a command can be optional.
+	Optional bool
+
+	// Variadic args take all the remaining args. Only the last arg of a command can be
+	// variadic, and its Flag must be a slice flag.
+	Variadic bool
+}
+
+// Synopsis returns the arg as it is rendered in usage lines and help text. ie: name, [name] or name...
+func (a Arg) Synopsis() string {
+	s := a.Flag.Name()
+	if a.Variadic {
+		s += "..."
+	}
+	if a.Optional {
+		s = "[" + s + "]"
+	}
+	return s
+}
+
+// parseArgs sets the values of the command's Args from the positional args in ctx
+func (c *Command) parseArgs(ctx *Context) error {
+	fs := flags.NewFlagSet()
+	ctx.namedArgs = flags.NewFlagGetter(fs)
+	if len(c.Args) == 0 {
+		return nil
+	}
+
+	invalid := func(pos int, err error) error {
+		return ErrInvalidArgs{Location: Location{CommandPath: c.path(), Position: pos}, Args: ctx.args, Err: err}
+	}
+	if n := len(c.Args); !c.Args[n-1].Variadic && len(ctx.args) > n {
+		return invalid(ctx.argPositions[n], fmt.Errorf("expected at most %d arguments, got %d", n, len(ctx.args)))
+	}
+
+	for i, arg := range c.Args {
+		f := arg.Flag
+		fs.AddFlag(f)
+		flags.Reset(f)
+
+		if i >= len(ctx.args) {
+			if err := flags.SetFromSources(f); err != nil {
+				return invalid(-1, fmt.Errorf("invalid value for argument %s: %w", f.Name(), err))
+			}
+			if !f.IsSet() && !arg.Optional {
+				return invalid(-1, fmt.Errorf("missing required argument %s", f.Name()))
+			}
+			continue
+		}
+
+		if !arg.Variadic {
+			if err := f.Set(ctx.args[i]); err != nil {
+				return invalid(ctx.argPositions[i], fmt.Errorf("invalid value %q for argument %s: %w", ctx.args[i], f.Name(), err))
+			}
+			continue
+		}
+		if err := flags.SetValues(f, ctx.args[i:]); err != nil {
+			// find the offending value to report its position
+			for j := i; j < len(ctx.args); j++ {
+				if err := flags.SetValues(f, ctx.args[j:j+1]); err != nil {
+					return invalid(ctx.argPositions[j], fmt.Errorf("invalid value %q for argument %s: %w", ctx.args[j], f.Name(), err))
+				}
+			}
+			return invalid(-1, err)
+		}
+	}
+	return nil
+}
+
+// argAt returns the Arg the positional arg at idx is parsed by
+func (c *Command) argAt(idx int) (Arg, bool) {
+	switch {
+	case len(c.Args) == 0:
+		return Arg{}, false
+	case idx < len(c.Args):
+		return c.Args[idx], true
+	case c.Args[len(c.Args)-1].Variadic:
+		return c.Args[len(c.Args)-1], true
+	}
+	return Arg{}, false
+}
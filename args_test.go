@@ -1,11 +1,20 @@
 package gommand_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
 )
 
 func Test_ArgValidator(t *testing.T) {
@@ -232,3 +241,270 @@ func Test_ArgValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestArgValidators(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing")
+
+	tests := []struct {
+		name      string
+		args      []string
+		validator gommand.ArgValidator
+		want      string
+	}{
+		{name: "one of valid", args: []string{"get", "set"}, validator: gommand.ArgsOneOf("get", "set")},
+		{
+			name: "one of invalid", args: []string{"get", "put"}, validator: gommand.ArgsOneOf("get", "set"),
+			want: `argument 1 "put": must be one of get, set`,
+		},
+		{name: "match valid", args: []string{"v1.2.3"}, validator: gommand.ArgsMatch(regexp.MustCompile(`^v\d+\.\d+\.\d+$`))},
+		{
+			name: "match invalid", args: []string{"1.2"}, validator: gommand.ArgsMatch(regexp.MustCompile(`^v\d+\.\d+\.\d+$`)),
+			want: `argument 0 "1.2": must match ^v\d+\.\d+\.\d+$`,
+		},
+		{name: "file valid", args: []string{file}, validator: gommand.ArgsFile()},
+		{name: "file missing", args: []string{missing}, validator: gommand.ArgsFile(), want: fmt.Sprintf("argument 0 %q: file does not exist", missing)},
+		{name: "file is dir", args: []string{dir}, validator: gommand.ArgsFile(), want: fmt.Sprintf("argument 0 %q: is a directory", dir)},
+		{name: "dir valid", args: []string{dir}, validator: gommand.ArgsDir()},
+		{name: "dir missing", args: []string{missing}, validator: gommand.ArgsDir(), want: fmt.Sprintf("argument 0 %q: directory does not exist", missing)},
+		{name: "dir is file", args: []string{file}, validator: gommand.ArgsDir(), want: fmt.Sprintf("argument 0 %q: not a directory", file)},
+		{name: "int valid", args: []string{"1", "-2"}, validator: gommand.ArgsInt()},
+		{
+			name: "int invalid reports every arg", args: []string{"a", "2", "1.5"}, validator: gommand.ArgsInt(),
+			want: `argument 0 "a": not an integer; argument 2 "1.5": not an integer`,
+		},
+		{name: "duration valid", args: []string{"1h30m"}, validator: gommand.ArgsDuration()},
+		{name: "duration invalid", args: []string{"10"}, validator: gommand.ArgsDuration(), want: `argument 0 "10": not a duration`},
+		{name: "url valid", args: []string{"https://example.com/path", "mailto:me@example.com"}, validator: gommand.ArgsURL()},
+		{name: "url invalid", args: []string{"example.com"}, validator: gommand.ArgsURL(), want: `argument 0 "example.com": not an absolute URL`},
+		{name: "unique valid", args: []string{"a", "b"}, validator: gommand.ArgsUnique()},
+		{
+			name: "unique invalid", args: []string{"a", "b", "a"}, validator: gommand.ArgsUnique(),
+			want: `argument 2 "a": already passed`,
+		},
+		{
+			name: "at", args: []string{"set", "x"}, validator: gommand.ArgsAt(1, gommand.ArgsInt()),
+			want: `argument 1 "x": not an integer`,
+		},
+		{name: "at not passed", args: []string{"set"}, validator: gommand.ArgsAt(1, gommand.ArgsInt())},
+		{
+			name: "at custom validator", args: []string{"set", "x"},
+			validator: gommand.ArgsAt(1, func([]string) error { return errors.New("bad") }),
+			want:      `argument 1 "x": bad`,
+		},
+		{
+			name: "rest", args: []string{"x", "1", "y"}, validator: gommand.ArgsRest(1, gommand.ArgsInt()),
+			want: `argument 2 "y": not an integer`,
+		},
+		{
+			name: "rest unique", args: []string{"a", "b", "c", "b"}, validator: gommand.ArgsRest(1, gommand.ArgsUnique()),
+			want: `argument 3 "b": already passed`,
+		},
+		{
+			name: "rest custom validator", args: []string{"a", "b", "c"},
+			validator: gommand.ArgsRest(1, func([]string) error { return errors.New("bad") }),
+			want:      `argument 1 "b": bad`,
+		},
+		{
+			name: "rest multiple errors", args: []string{"a", "b", "c"},
+			validator: gommand.ArgsRest(1, gommand.ArgsInt()),
+			want:      `argument 1 "b": not an integer; argument 2 "c": not an integer`,
+		},
+		{name: "at out of range", args: []string{"a"}, validator: gommand.ArgsAt(5, gommand.ArgsInt())},
+		{name: "rest out of range", args: []string{"a"}, validator: gommand.ArgsRest(1, gommand.ArgsInt())},
+		{name: "at negative", args: []string{"a"}, validator: gommand.ArgsAt(-1, gommand.ArgsInt()), want: "invalid arg index -1"},
+		{name: "rest negative", args: []string{"a"}, validator: gommand.ArgsRest(-2, gommand.ArgsInt()), want: "invalid arg index -2"},
+		{
+			name: "some", args: []string{"a"}, validator: gommand.ArgsSome(gommand.ArgsMin(2), gommand.ArgsInt()),
+			want: `no validators passed: expected at least 2 arguments, got 1; argument 0 "a": not an integer`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validator(tt.args)
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("got %v, want %s", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("position", func(t *testing.T) {
+		cmd := &gommand.Command{
+			Name:         "test",
+			ArgValidator: gommand.ArgsEvery(gommand.ArgsExact(2), gommand.ArgsAt(0, gommand.ArgsOneOf("get", "set")), gommand.ArgsRest(1, gommand.ArgsInt())),
+			FlagSet:      flags.NewFlagSet().AddFlags(flags.BoolFlagS("verbose", 'v', false, "")),
+			SilenceHelp:  true,
+			SilenceError: true,
+			Run:          func(*gommand.Context) error { return nil },
+		}
+		err := cmd.ExecuteArgs(context.Background(), []string{"-v", "get", "x"})
+		var target gommand.ErrInvalidArgs
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrInvalidArgs, got %T: %v", err, err)
+		}
+		if target.Position != 2 {
+			t.Errorf("got position %d, want 2", target.Position)
+		}
+		var argErr gommand.ErrArg
+		if !errors.As(err, &argErr) || argErr.Index != 1 || argErr.Value != "x" {
+			t.Errorf("expected ErrArg for argument 1, got %#v", argErr)
+		}
+	})
+}
+
+func TestNamedArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		env     string
+		want    string
+		wantErr string
+		wantPos int
+	}{
+		{name: "all args", args: []string{"api", "3", "a=b,c", "d"}, want: `api 3 ["a=b,c" "d"]`},
+		{name: "optional args", args: []string{"api"}, want: "api 1 []"},
+		{name: "env fallback", args: []string{"api"}, env: "5", want: "api 5 []"},
+		{name: "args take precedence over env", args: []string{"api", "2"}, env: "5", want: "api 2 []"},
+		{name: "missing required", args: nil, wantErr: "gommand: invalid args: missing required argument deployment", wantPos: -1},
+		{name: "invalid value", args: []string{"api", "three"}, wantErr: `gommand: invalid args: invalid value "three" for argument replicas: strconv.ParseInt: parsing "three": invalid syntax`, wantPos: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("REPLICAS", tt.env)
+			if tt.env == "" {
+				_ = os.Unsetenv("REPLICAS")
+			}
+			var buf bytes.Buffer
+			root := &gommand.Command{Name: "mytool", SilenceHelp: true, SilenceError: true}
+			root.SubCommand(&gommand.Command{
+				Name:  "scale",
+				Usage: "scale a deployment",
+				Args: []gommand.Arg{
+					{Flag: flags.Completion(flags.StringFlag("deployment", "", "deployment to scale"), func(flags.CompletionContext, string) ([]string, flags.CompletionDirective) {
+						return []string{"api", "web"}, flags.CompleteNoFile
+					})},
+					{Flag: flags.IntFlag("replicas", 1, "number of replicas").AddSources(flags.Environ), Optional: true},
+					{Flag: flags.StringSliceFlag("labels", nil, "labels to set"), Optional: true, Variadic: true},
+				},
+				Run: func(ctx *gommand.Context) error {
+					args := ctx.NamedArgs()
+					_, err := fmt.Fprintf(&buf, "%s %d %q", args.String("deployment"), args.Int("replicas"), args.StringSlice("labels"))
+					return err
+				},
+			})
+
+			err := root.ExecuteArgs(context.Background(), append([]string{"scale"}, tt.args...))
+			if tt.wantErr != "" {
+				var target gommand.ErrInvalidArgs
+				if !errors.As(err, &target) {
+					t.Fatalf("expected ErrInvalidArgs, got %T: %v", err, err)
+				}
+				if err.Error() != tt.wantErr || target.Position != tt.wantPos {
+					t.Errorf("got error %q at %d, want %q at %d", err, target.Position, tt.wantErr, tt.wantPos)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("too many args", func(t *testing.T) {
+		cmd := &gommand.Command{
+			Name: "get",
+			Args: []gommand.Arg{{Flag: flags.StringFlag("name", "", "")}},
+			Run:  func(*gommand.Context) error { return nil },
+		}
+		err := cmd.ExecuteArgs(context.Background(), []string{"a", "b"}, gommand.WithStderr(&bytes.Buffer{}))
+		var target gommand.ErrInvalidArgs
+		if !errors.As(err, &target) || target.Position != 1 {
+			t.Fatalf("expected ErrInvalidArgs at position 1, got %v", err)
+		}
+	})
+
+	t.Run("invalid variadic value", func(t *testing.T) {
+		cmd := &gommand.Command{
+			Name: "sum",
+			Args: []gommand.Arg{{Flag: flags.IntSliceFlag("n", nil, ""), Variadic: true}},
+			Run:  func(*gommand.Context) error { return nil },
+		}
+		err := cmd.ExecuteArgs(context.Background(), []string{"1", "2", "x", "4"}, gommand.WithStderr(&bytes.Buffer{}))
+		var target gommand.ErrInvalidArgs
+		if !errors.As(err, &target) || target.Position != 2 {
+			t.Fatalf("expected ErrInvalidArgs at position 2, got %v", err)
+		}
+	})
+
+	t.Run("no named args", func(t *testing.T) {
+		cmd := &gommand.Command{Name: "get", ArgValidator: gommand.ArgsAny(), Run: func(ctx *gommand.Context) error {
+			if _, err := ctx.NamedArgs().LookupString("name"); err == nil {
+				t.Error("expected error looking up undeclared arg")
+			}
+			return nil
+		}}
+		if err := cmd.ExecuteArgs(context.Background(), []string{"a"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("help", func(t *testing.T) {
+		cmd := &gommand.Command{
+			Name: "scale",
+			Args: []gommand.Arg{
+				{Flag: flags.StringFlag("deployment", "", "deployment to scale")},
+				{Flag: flags.IntFlag("replicas", 1, "number of replicas"), Optional: true},
+				{Flag: flags.StringSliceFlag("labels", nil, "labels to set"), Optional: true, Variadic: true},
+			},
+			Run: func(*gommand.Context) error { return nil },
+		}
+
+		var buf bytes.Buffer
+		if err := cmd.ExecuteArgs(context.Background(), []string{"--help"}, gommand.WithStdout(&buf)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, want := range []string{
+			"Usage:\n  scale deployment [replicas] [labels...]\n",
+			"Arguments:\n  deployment   deployment to scale\n  [replicas]   number of replicas\n  [labels...]  labels to set\n\n",
+		} {
+			if !strings.Contains(buf.String(), want) {
+				t.Errorf("expected help text to contain %q, got:\n%s", want, buf.String())
+			}
+		}
+	})
+
+	t.Run("completion", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool"}
+		root.SubCommand(&gommand.Command{
+			Name: "scale",
+			Args: []gommand.Arg{
+				{Flag: flags.Completion(flags.StringFlag("deployment", "", "deployment to scale"), func(flags.CompletionContext, string) ([]string, flags.CompletionDirective) {
+					return []string{"api", "web"}, flags.CompleteNoFile
+				})},
+			},
+			Run: func(*gommand.Context) error { return nil },
+		})
+
+		var buf bytes.Buffer
+		args := []string{"__complete", "--", "scale", ""}
+		if err := root.ExecuteArgs(context.Background(), args, gommand.WithStdout(&buf), gommand.WithCompletionCommand()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := strings.Split(buf.String(), "\n"), []string{"api", "web", ":2", ""}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+}
@@ -4,19 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"slices"
 	"sort"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/jimmykodes/gommand/flags"
 	"github.com/jimmykodes/gommand/internal/lexer"
-)
-
-var (
-	ErrNoRunner    = errors.New("gommand: command has no run function")
-	errShowHelp    = errors.New("show help")
-	errShowVersion = errors.New("show version")
+	"github.com/jimmykodes/gommand/internal/suggest"
 )
 
 // Command represents a command line command
@@ -59,6 +56,9 @@ type Command struct {
 	//       enclosed in { }
 	//
 	// Example: create {--from-file file | --from-gcs bucket} [-d destination] file_name...
+	//
+	// By default, the usage descriptions are only printed in help text. See EnforceUsage to validate
+	// the command line against them.
 	Name string
 
 	// Usage is the short explanation of the command
@@ -81,6 +81,18 @@ type Command struct {
 	// All are valid ways of executing the `list` command
 	Aliases []string
 
+	// DeprecatedAliases are aliases for the current command that are deprecated, mapped to the
+	// deprecation message printed as a warning when they are used. They are not shown in help text.
+	// This allows a command to be renamed while the old name keeps working for a while.
+	//
+	// Ex:
+	// c := &Command{Name: "run", DeprecatedAliases: map[string]string{"start": `use "run" instead`}}
+	DeprecatedAliases map[string]string
+
+	// Deprecated marks the command as deprecated. The message is printed as a warning when the
+	// command is used, and should tell the user what to use instead. Deprecated commands are hidden.
+	Deprecated string
+
 	// Version is the value that will be printed when `--version` is passed to the command.
 	// When retrieving the command version, the call tree is traversed backwards until a Command
 	// is reached that has a non-zero value for the version. This means that it is possible
@@ -92,9 +104,26 @@ type Command struct {
 
 	// ArgValidator is an ArgValidator to be called on the args of the function being executed. This is called before any of
 	// the functions for this command are called.
-	// If this is not defined ArgsNone is used.
+	// If this is not defined ArgsNone is used, unless the command declares Args, in which case ArgsAny is used.
 	ArgValidator ArgValidator
 
+	// Args declares the named positional args of the command, in order. After the ArgValidator,
+	// the positional args are parsed into them, using the converters of their flag types, and
+	// validated against them. Their values are available by name from Context.NamedArgs.
+	//
+	// Args are listed in the help text, and if the Name of the command includes no usage
+	// descriptions, they are appended to its usage line.
+	//
+	// ex:
+	// c := &Command{
+	// 	Name: "scale",
+	// 	Args: []Arg{
+	// 		{Flag: flags.StringFlag("deployment", "", "deployment to scale")},
+	// 		{Flag: flags.IntFlag("replicas", 1, "number of replicas"), Optional: true},
+	// 	},
+	// }
+	Args []Arg
+
 	// Flags are a slice of flags.Flag that will be used to initialize the command's FlagSet
 	FlagSet *flags.FlagSet
 
@@ -162,27 +191,142 @@ type Command struct {
 	// point of a command's upstream lineage has the value set, the help message will be silenced
 	SilenceHelp bool
 
+	// HelpTemplate is a text/template used to render the command's help text, executed with HelpData.
+	// If it is not set, the HelpTemplate of the closest parent that sets one is used, falling
+	// back to DefaultHelpTemplate. This allows a template set on the root command to apply to
+	// the whole tree while still letting individual subcommands override it.
+	HelpTemplate string
+
+	// Hidden hides the command from its parent's help text, generated docs and completions.
+	// A hidden command can still be executed.
+	Hidden bool
+
 	// SilenceError is like SilenceHelp but does not print the "Error: xxx" message when the command
 	// exits with an error
 	SilenceError bool
 
+	// EnforceUsage validates the command line against the usage descriptions in the Name of the
+	// command, or the synopsis of its Args if its Name has none, after any ArgValidator, which
+	// defaults to ArgsAny rather than ArgsNone. The usage is enforced as follows:
+	//   - positional args must be passed as many times as the usage allows, so with a usage of
+	//     src [dest...], at least one arg is required
+	//   - flags outside of [ ] must be set
+	//   - flags in different alternatives of a [ ] or { } group must not be set together
+	//   - one alternative of a { } group must be set, unless it is made of positional args
+	//
+	// A mismatch is returned as an ErrUsage quoting the offending part of the usage.
+	// Like DeferPost, this value is persistent, so it applies to all subcommands from where it is set
+	EnforceUsage bool
+
+	// PrefixMatching allows long flags and subcommands (including aliases) to be referenced by
+	// any unambiguous prefix of their name. ie: --verb for --verbose or ser for server.
+	// An exact match always takes precedence, and a prefix that matches more than one flag or
+	// subcommand is an error. Hidden and deprecated flags and subcommands only match their full name.
+	// Like DeferPost, this value is persistent, so it applies to all subcommands from where it is set
+	PrefixMatching bool
+
+	// Complete returns the candidates for completing a positional arg of the command, given the
+	// partial arg typed so far. ctx holds the flags and args already parsed from the command line
+	// being completed. See flags.CompletionFunc for the format of candidates, and
+	// WithCompletionCommand for enabling dynamic completion.
+	Complete func(ctx *Context, toComplete string) ([]string, flags.CompletionDirective)
+
+	// Group is the ID of the group, defined in the parent's Groups, that the command is
+	// listed under in its parent's help text. Commands without a group are listed under
+	// "Additional Commands" if the parent defines any groups, or "Available Commands" otherwise.
+	Group string
+
+	// Groups are the sections subcommands can be listed under in the help text, printed in
+	// the order they are defined.
+	//
+	// ex:
+	// c := &Command{
+	// 	Name: "mytool",
+	// 	Groups: []Group{
+	// 		{ID: "manage", Title: "Management Commands"},
+	// 		{ID: "debug", Title: "Debug Commands"},
+	// 	},
+	// }
+	// c.SubCommand(&Command{Name: "volume", Group: "manage"}, &Command{Name: "trace", Group: "debug"})
+	Groups []Group
+
+	// DeclarationOrder lists subcommands in the help text in the order they were registered
+	// instead of alphabetically.
+	DeclarationOrder bool
+
+	// Plugins allows the command tree to be extended by other programs without recompiling it.
+	// When set on the root of the tree, a subcommand foo that is not defined is executed as
+	// the program <name>-foo, found in PluginDirs or on $PATH, with the args following it.
+	//
+	// The plugin inherits Stdin, Stdout and Stderr, and receives the values of the persistent
+	// flags passed before it as environment variables, named as flags.Environ reads them.
+	// If it exits with a non-zero exit code, ErrPluginExit is returned.
+	//
+	// ex:
+	// mytool --cluster prod deploy --dry-run
+	//
+	// runs `mytool-deploy --dry-run` with CLUSTER=prod, if mytool has no deploy subcommand.
+	// Discovered plugins are listed in the help text.
+	Plugins bool
+
+	// PluginDirs are directories searched for plugins before $PATH
+	PluginDirs []string
+
+	// UserAliases are aliases defined by the user, rather than at compile time, that expand
+	// to a command line. When set on the root of the tree, a first arg naming an alias is
+	// replaced by its shell-word split expansion before parsing, so
+	//
+	// mytool deploy-prod --dry-run
+	//
+	// runs `mytool deploy --env prod --confirm --dry-run` given the alias
+	// deploy-prod = deploy --env prod --confirm
+	//
+	// Expansions may begin with another alias, but aliases can not shadow subcommands or
+	// expand to themselves. User aliases are listed in the help text. See AliasMap and AliasFile.
+	UserAliases AliasSource
+
 	parent   *Command
 	commands commands
+	// children are the registered subcommands, in the order they were registered
+	children []*Command
+	// noHooks skips the pre and post runs and the required flag checks of the command
+	// and its parents, ie: for __complete, which is run on a partial command line
+	noHooks bool
 }
 
 func (c *Command) ExecuteContext(ctx context.Context, opts ...ExecutionOption) error {
 	cmdCtx := &Context{
 		Context: ctx,
-		lexer:   lexer.New(os.Args[1:]),
+		cmd:     c,
+		argv:    os.Args[1:],
 	}
 	for _, opt := range opts {
 		opt.Apply(cmdCtx)
 	}
 
-	err := c.execute(cmdCtx)
+	// built-in commands are only registered for the duration of the execution, so the
+	// tree is left as it was declared and options can differ between executions
+	for _, builtin := range c.builtins(cmdCtx) {
+		c.subCommand(builtin)
+		defer c.removeCommand(builtin)
+	}
+
+	args, origins, err := c.expandAliases(cmdCtx, cmdCtx.argv)
+	if err == nil && cmdCtx.responseFileDepth > 0 {
+		var fileOrigins []int
+		args, fileOrigins, err = lexer.ExpandResponseFiles(args, cmdCtx.responseFileDepth)
+		for i, origin := range fileOrigins {
+			fileOrigins[i] = origins[origin]
+		}
+		origins = fileOrigins
+	}
+	if err == nil {
+		cmdCtx.lexer = lexer.New(args)
+		cmdCtx.origins = origins
+		err = c.execute(cmdCtx)
+	}
 	if errors.Is(err, errShowHelp) {
-		_, _ = fmt.Fprint(cmdCtx.Stdout(), cmdCtx.cmd.helpText())
-		return nil
+		return cmdCtx.cmd.writeHelp(cmdCtx, cmdCtx.Stdout())
 	}
 	if errors.Is(err, errShowVersion) {
 		v := c._version()
@@ -194,8 +338,12 @@ func (c *Command) ExecuteContext(ctx context.Context, opts ...ExecutionOption) e
 	}
 
 	if mErr := errors.Join(err, cmdCtx.err); mErr != nil {
+		if errors.As(mErr, &ErrPluginExit{}) {
+			// the plugin is expected to report its own errors
+			return mErr
+		}
 		if !cmdCtx.silenceHelp {
-			_, _ = fmt.Fprint(cmdCtx.Stderr(), cmdCtx.cmd.helpText())
+			_ = cmdCtx.cmd.writeHelp(cmdCtx, cmdCtx.Stderr())
 		}
 		if !cmdCtx.silenceError {
 			_, _ = fmt.Fprintln(cmdCtx.Stderr(), "Error:", mErr)
@@ -209,6 +357,12 @@ func (c *Command) Execute(opts ...ExecutionOption) error {
 	return c.ExecuteContext(context.Background(), opts...)
 }
 
+// ExecuteArgs executes the command tree against args rather than os.Args[1:].
+// args should not include the program name.
+func (c *Command) ExecuteArgs(ctx context.Context, args []string, opts ...ExecutionOption) error {
+	return c.ExecuteContext(ctx, append([]ExecutionOption{WithArgs(args)}, opts...)...)
+}
+
 func (c *Command) SubCommand(cmds ...*Command) {
 	for _, command := range cmds {
 		c.subCommand(command)
@@ -220,6 +374,16 @@ func (c *Command) name() (name string) {
 	return
 }
 
+// path returns the names of the commands from the root of the tree to c.
+// ie: mytool server run
+func (c *Command) path() string {
+	path := []string{c.name()}
+	for parent := c.parent; parent != nil; parent = parent.parent {
+		path = append([]string{parent.name()}, path...)
+	}
+	return strings.Join(path, " ")
+}
+
 func (c *Command) _version() string {
 	_c := c
 	for _c.Version == "" {
@@ -231,64 +395,10 @@ func (c *Command) _version() string {
 	return _c.Version
 }
 
-func (c *Command) helpText() string {
-	var sb strings.Builder
-
-	if c.Description != "" {
-		sb.WriteString(c.Description)
-		sb.WriteString("\n\n")
-	} else if c.Usage != "" {
-		sb.WriteString(c.Usage)
-		sb.WriteString("\n\n")
-	}
-
-	sb.WriteString("Usage:\n")
-	usage := []string{c.Name}
-	for parent := c.parent; parent != nil; parent = parent.parent {
-		usage = append([]string{parent.name()}, usage...)
-	}
-	sb.WriteString("  " + strings.Join(usage, " "))
-
-	if len(c.commands) > 0 {
-		sb.WriteString(" [commands]")
-	}
-
-	sb.WriteString("\n\n")
-
-	if len(c.Aliases) > 0 {
-		sb.WriteString("Aliases:\n")
-		_, _ = fmt.Fprintf(&sb, "  %s\n\n", strings.Join(c.Aliases, ", "))
-	}
-
-	fs := flags.NewFlagSet(flags.WithHelpFlag()).AddFlagSet(c.FlagSet)
-	pfs := flags.NewFlagSet()
-
-	for p := c; p != nil; p = p.parent {
-		pfs.AddFlagSet(p.PersistentFlagSet)
-	}
-
-	if len(c.commands) > 0 {
-		sb.WriteString("Available Commands:\n")
-		sb.WriteString(c.commands.String() + "\n")
-	}
-
-	fsStr := fs.Repr()
-	pfsStr := pfs.Repr()
-
-	sb.WriteString("Flags:\n")
-	sb.WriteString(fsStr + "\n")
-
-	if pfsStr != "" {
-		sb.WriteString("\nGlobal Flags:\n")
-		sb.WriteString(pfsStr + "\n")
-	}
-
-	return sb.String()
-}
-
 func (c *Command) subCommand(cmd *Command) {
-	// todo: how to handle commands with the same name
-	// 	warn? silently overwrite? return err?
+	// commands registered with the same name or alias silently overwrite
+	// each other here, Validate reports these collisions.
+	c.children = append(c.children, cmd)
 	if c.commands == nil {
 		c.commands = make(map[string]*Command)
 	}
@@ -296,9 +406,47 @@ func (c *Command) subCommand(cmd *Command) {
 	for _, alias := range cmd.Aliases {
 		c.commands[alias] = cmd
 	}
+	for alias := range cmd.DeprecatedAliases {
+		c.commands[alias] = cmd
+	}
 	cmd.parent = c
 }
 
+// removeCommand unregisters cmd, which must have been registered by subCommand
+func (c *Command) removeCommand(cmd *Command) {
+	c.children = slices.DeleteFunc(c.children, func(child *Command) bool { return child == cmd })
+	maps.DeleteFunc(c.commands, func(_ string, registered *Command) bool { return registered == cmd })
+	cmd.parent = nil
+}
+
+// builtins returns the built-in commands enabled by the options in ctx, skipping any
+// that would shadow a subcommand of c
+func (c *Command) builtins(ctx *Context) []*Command {
+	var out []*Command
+	for _, builtin := range []struct {
+		enabled bool
+		cmd     func() *Command
+	}{
+		{enabled: ctx.helpCommand, cmd: newHelpCommand},
+		{enabled: ctx.completionCommand, cmd: newCompletionCommand},
+		{enabled: ctx.completionCommand, cmd: newCompleteCommand},
+	} {
+		if !builtin.enabled {
+			continue
+		}
+		cmd := builtin.cmd()
+		if _, ok := c.commands[cmd.name()]; !ok {
+			out = append(out, cmd)
+		}
+	}
+	return out
+}
+
+// Parent returns the command c is registered as a subcommand of, or nil if it is the root of the tree
+func (c *Command) Parent() *Command {
+	return c.parent
+}
+
 func (c *Command) hasSubCommands() bool {
 	return len(c.commands) > 0
 }
@@ -310,6 +458,10 @@ func (c *Command) execute(ctx *Context) error {
 	ctx.cmd = c
 	ctx.addPersistentFlags(c.PersistentFlagSet)
 
+	if c.Deprecated != "" {
+		ctx.warnDeprecated(fmt.Sprintf("command %q", c.path()), c.Deprecated)
+	}
+
 	// append pre run functions to be executed in order
 	ctx.preRuns = append(ctx.preRuns, func(ctx *Context) error { return nil })
 	if c.PersistentPreRun != nil {
@@ -333,6 +485,14 @@ func (c *Command) execute(ctx *Context) error {
 		ctx.silenceHelp = true
 	}
 
+	if c.PrefixMatching {
+		ctx.prefixMatching = true
+	}
+
+	if c.EnforceUsage {
+		ctx.enforceUsage = true
+	}
+
 	fs := flags.NewFlagSet()
 
 	fs.AddFlagSet(ctx.persistentFlags())
@@ -354,38 +514,106 @@ func (c *Command) execute(ctx *Context) error {
 			if c.hasSubCommands() {
 				// this is a bare value, it could be an arg
 				// or it could be a sub command
-				if next, ok := c.commands[token.Value]; ok {
+				next, err := c.commands.lookup(token.Value, ctx.prefixMatching)
+				if err != nil {
+					var ambiguous ErrAmbiguousCommand
+					if errors.As(err, &ambiguous) {
+						ambiguous.Location = c.location(ctx)
+						return ambiguous
+					}
+					return err
+				}
+				if next != nil {
 					if len(ctx.args) > 0 {
 						// an arg was already encountered that did not
 						// match a subcommand, and thus stored as a ctx.arg
 						// but now there is an arg that _is_ a subcommand.
 						// this throws an error.
-						return fmt.Errorf("gommand: invalid arg ordering. arguments %v appear before subcommand %s", ctx.args, token.Value)
+						return ErrMisorderedArgs{Location: c.location(ctx), Args: ctx.args, Command: token.Value}
+					}
+					if msg, ok := next.DeprecatedAliases[token.Value]; ok {
+						ctx.warnDeprecated(fmt.Sprintf("command alias %q", token.Value), msg)
 					}
 					// found the next command
 					ctx.depth++
 					return next.execute(ctx)
 				}
 			}
+			if len(ctx.args) == 0 {
+				if path, ok := c.lookupPlugin(token.Value); ok {
+					return c.runPlugin(ctx, token.Value, path)
+				}
+			}
 			// no sub commands, store the arg
+			pos := ctx.lastPosition()
 			ctx.args = append(ctx.args, token.Value)
+			ctx.argPositions = append(ctx.argPositions, pos)
 
-		case lexer.MultiFlagType:
-			if token.Value != "" {
-				return fmt.Errorf("gommand: invalid multi-flag: cannot assign value to multi-flag: -%s", token.Name)
+		case lexer.TerminatorType:
+			// everything after `--` is passed through untouched, never
+			// treated as a flag or a subcommand.
+			for t := ctx.lexer.Read(); t != nil; t = ctx.lexer.Read() {
+				ctx.passthrough = append(ctx.passthrough, t.Value)
 			}
-			for _, chr := range token.Name {
+
+		case lexer.MultiFlagType:
+			// clustered short flags are parsed left to right. Boolean flags are set to true,
+			// count flags are incremented, and the first flag of any other type consumes the
+			// rest of the cluster as its value, or the next token if it is last in the cluster.
+			// ie: -vo out.txt, -vout.txt and -vo=out.txt are all equivalent to -v -o out.txt
+			var (
+				consumed bool
+				f        flags.Flag
+			)
+			for i, chr := range token.Name {
 				if chr == 'h' {
 					return errShowHelp
 				}
-				f := fs.FromShort(chr)
+				f = fs.FromShort(chr)
 				if f == nil {
-					return fmt.Errorf("gommand: missing flag: -%s", string(chr))
+					return ErrUnknownFlag{Location: c.location(ctx), Flag: "-" + string(chr)}
+				}
+				ctx.flagRead(f)
+				if msg := flags.Deprecation(f); msg != "" {
+					ctx.warnDeprecated("flag --"+f.Name(), msg)
+				}
+				switch f.Type() {
+				case flags.BoolFlagType:
+					_ = f.Set("true")
+					continue
+				case flags.CountFlagType:
+					if err := flags.Increment(f); err != nil {
+						return c.invalidFlagValue(ctx, f, "", err)
+					}
+					continue
+				}
+
+				value := token.Name[i+utf8.RuneLen(chr):]
+				if value == "" {
+					value = token.Value
+				} else if token.Value != "" {
+					// the lexer split the cluster on `=`, which is part of the value.
+					// ie: -ofile=x sets -o to file=x
+					value += "=" + token.Value
+				}
+				if value == "" {
+					if implicit, ok := flags.ImplicitValue(f); ok {
+						value = implicit
+					} else if peekToken := ctx.lexer.Peek(); peekToken != nil && peekToken.Type == lexer.ValueType {
+						value = ctx.lexer.Read().Value
+					}
 				}
-				if f.Type() != flags.BoolFlagType {
-					return fmt.Errorf("gommand: invalid multi-flag: -%s is not a boolean flag", string(chr))
+				if value == "" {
+					return c.invalidFlagValue(ctx, f, "", errMissingFlagValue)
 				}
-				_ = f.Set("true")
+				if err := f.Set(value); err != nil {
+					return c.invalidFlagValue(ctx, f, value, err)
+				}
+				consumed = true
+				break
+			}
+			if !consumed && token.Value != "" {
+				return c.invalidFlagValue(ctx, f, token.Value, errMultiFlagValue)
 			}
 		default:
 			var f flags.Flag
@@ -402,34 +630,65 @@ func (c *Command) execute(ctx *Context) error {
 				if token.Name == "version" {
 					return errShowVersion
 				}
-				f = fs.FromName(token.Name)
+				if ctx.prefixMatching {
+					var err error
+					if f, err = fs.FromPrefix(token.Name); err != nil {
+						var ambiguous flags.ErrAmbiguousFlag
+						if errors.As(err, &ambiguous) {
+							return ErrAmbiguousFlag{Location: c.location(ctx), Prefix: ambiguous.Prefix, Matches: ambiguous.Matches}
+						}
+						return err
+					}
+				} else {
+					f = fs.FromName(token.Name)
+				}
 			}
 
 			if f == nil {
-				prefix := "-"
 				if token.Type == lexer.LongFlagType {
-					prefix += "-"
+					return ErrUnknownFlag{
+						Location:    c.location(ctx),
+						Flag:        "--" + token.Name,
+						Suggestions: flagSuggestions(fs, token.Name),
+					}
 				}
-				return fmt.Errorf("gommand: missing flag: %s%s", prefix, token.Name)
+				return ErrUnknownFlag{Location: c.location(ctx), Flag: "-" + token.Name}
+			}
+			ctx.flagRead(f)
+			if msg := flags.Deprecation(f); msg != "" {
+				ctx.warnDeprecated("flag --"+f.Name(), msg)
 			}
 
-			var setErr error
-			if token.Value != "" {
+			var (
+				value  = token.Value
+				setErr error
+			)
+			if value != "" {
 				// the token has a value attached to it via `=`
 				// so set that value on the flag
-				setErr = f.Set(token.Value)
+				setErr = f.Set(value)
 			} else {
 				// the token has no value, consume the next token as the value
-				if f.Type() == flags.BoolFlagType {
+				switch f.Type() {
+				case flags.BoolFlagType:
 					setErr = f.Set("true")
-				} else {
-					if peekToken := ctx.lexer.Peek(); peekToken != nil && peekToken.Type == lexer.ValueType {
-						setErr = f.Set(ctx.lexer.Read().Value)
+				case flags.CountFlagType:
+					setErr = flags.Increment(f)
+				default:
+					if implicit, ok := flags.ImplicitValue(f); ok {
+						// flags with an implicit value only accept values through `=`
+						value = implicit
+						setErr = f.Set(value)
+					} else if peekToken := ctx.lexer.Peek(); peekToken != nil && peekToken.Type == lexer.ValueType {
+						value = ctx.lexer.Read().Value
+						setErr = f.Set(value)
+					} else {
+						setErr = errMissingFlagValue
 					}
 				}
 			}
 			if setErr != nil {
-				return setErr
+				return c.invalidFlagValue(ctx, f, value, setErr)
 			}
 		}
 	}
@@ -442,9 +701,42 @@ func (c *Command) execute(ctx *Context) error {
 	if validator == nil {
 		// default to allowing no args unless specified otherwise.
 		validator = ArgsNone()
+		if len(c.Args) > 0 || ctx.enforceUsage {
+			validator = ArgsAny()
+		}
 	}
 	if err := validator(ctx.args); err != nil {
-		return fmt.Errorf("gommand: invalid args: %w", err)
+		if cmdErr := c.unknownCommand(ctx); cmdErr != nil {
+			return cmdErr
+		}
+		// point at the first arg the validator rejected, if it names one
+		pos := -1
+		var argErr ErrArg
+		if errors.As(err, &argErr) && argErr.Index >= 0 && argErr.Index < len(ctx.argPositions) {
+			pos = ctx.argPositions[argErr.Index]
+		}
+		return ErrInvalidArgs{Location: Location{CommandPath: c.path(), Position: pos}, Args: ctx.args, Err: err}
+	}
+	if ctx.enforceUsage && c.Run != nil {
+		if err := c.checkUsage(ctx, fs); err != nil {
+			if cmdErr := c.unknownCommand(ctx); cmdErr != nil {
+				return cmdErr
+			}
+			return err
+		}
+	}
+
+	if err := c.parseArgs(ctx); err != nil {
+		if cmdErr := c.unknownCommand(ctx); cmdErr != nil {
+			return cmdErr
+		}
+		return err
+	}
+
+	if c.Run == nil {
+		if cmdErr := c.unknownCommand(ctx); cmdErr != nil {
+			return cmdErr
+		}
 	}
 
 	// ################
@@ -453,11 +745,64 @@ func (c *Command) execute(ctx *Context) error {
 	return c.run(ctx)
 }
 
+// unknownCommand returns ErrUnknownCommand if the first arg passed to c looks
+// like a mistyped subcommand, otherwise nil.
+func (c *Command) unknownCommand(ctx *Context) error {
+	if !c.hasSubCommands() || len(ctx.args) == 0 {
+		return nil
+	}
+	suggestions := suggest.Closest(ctx.args[0], slices.Collect(maps.Keys(c.commands.visible())))
+	if len(suggestions) == 0 {
+		return nil
+	}
+	return ErrUnknownCommand{
+		Location:    Location{CommandPath: c.path(), Position: ctx.argPositions[0]},
+		Name:        ctx.args[0],
+		Suggestions: suggestions,
+	}
+}
+
+// location returns the Location of the most recently read argument
+func (c *Command) location(ctx *Context) Location {
+	return Location{CommandPath: c.path(), Position: ctx.lastPosition()}
+}
+
+// invalidFlagValue wraps err, returned when setting value on f, with the location of the
+// most recently read argument
+func (c *Command) invalidFlagValue(ctx *Context, f flags.Flag, value string, err error) error {
+	_, token := ctx.lexer.Last()
+	return ErrInvalidFlagValue{
+		Location: Location{CommandPath: c.path(), Position: ctx.lastPosition()},
+		Flag:     f,
+		Token:    token,
+		Value:    value,
+		Err:      err,
+	}
+}
+
+// flagSuggestions returns the long flags in fs that are likely what was meant by name
+func flagSuggestions(fs *flags.FlagSet, name string) []string {
+	names := []string{"help", "version"}
+	for n, f := range flags.NewFlagGetter(fs).All() {
+		if !flags.IsHidden(f) {
+			names = append(names, n)
+		}
+	}
+	suggestions := suggest.Closest(name, names)
+	for i, s := range suggestions {
+		suggestions[i] = "--" + s
+	}
+	return suggestions
+}
+
 func (c *Command) run(ctx *Context) (runErr error) {
 	// if there is no Run command, no need to do pre/post run setup things
 	if c.Run == nil {
 		return ErrNoRunner
 	}
+	if c.noHooks {
+		return c.Run(ctx)
+	}
 
 	for depth, run := range ctx.preRuns {
 		fs := ctx.persistentFlagSets[depth]
@@ -538,27 +883,57 @@ func (c *Command) run(ctx *Context) (runErr error) {
 	return runErr
 }
 
+// Group is a titled section of subcommands in a command's help text
+type Group struct {
+	// ID is the value subcommands set as their Group to be listed in this group
+	ID string
+	// Title is the heading the group is printed under. ie: Management Commands
+	Title string
+}
+
 type commands map[string]*Command
 
-func (c commands) String() string {
+// lookup returns the command registered with the given name or alias. If prefix is true
+// and there is no exact match, the command uniquely identified by the prefix name is returned.
+// If no command matches, both return values are nil.
+func (c commands) lookup(name string, prefix bool) (*Command, error) {
+	if cmd, ok := c[name]; ok || !prefix || name == "" {
+		return cmd, nil
+	}
 	var (
-		sb     strings.Builder
-		maxKey int
-		keys   = make([]string, 0, len(c))
+		found   = make(map[*Command]bool)
+		matches []string
 	)
+	// hidden and deprecated commands can only be run by their full name
+	for key, cmd := range c.visible() {
+		if strings.HasPrefix(key, name) {
+			found[cmd] = true
+			matches = append(matches, key)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return c[matches[0]], nil
+	default:
+		sort.Strings(matches)
+		return nil, ErrAmbiguousCommand{Prefix: name, Matches: matches}
+	}
+}
+
+// visible returns the commands that are not hidden or deprecated, keyed by their
+// name and any aliases that are not deprecated.
+func (c commands) visible() commands {
+	out := make(commands, len(c))
 	for name, command := range c {
-		if slices.Contains(command.Aliases, name) {
+		if command.Hidden || command.Deprecated != "" {
 			continue
 		}
-		keys = append(keys, name)
-		if l := len(name); l > maxKey {
-			maxKey = l
+		if _, ok := command.DeprecatedAliases[name]; ok {
+			continue
 		}
+		out[name] = command
 	}
-	sort.Strings(keys)
-	for _, k := range keys {
-		padding := maxKey - len(k)
-		_, _ = fmt.Fprintf(&sb, "  %s%s  %s\n", k, strings.Repeat(" ", padding), c[k].Usage)
-	}
-	return sb.String()
+	return out
 }
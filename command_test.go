@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -51,6 +53,549 @@ func TestSimpleCommand(t *testing.T) {
 	}
 }
 
+func TestExecuteArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "single arg", args: []string{"sub", "a"}},
+		{name: "many args", args: []string{"sub", "a", "b", "c"}},
+		{name: "no args", args: []string{"sub"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			t.Run("ExecuteArgs", func(t *testing.T) {
+				var got []string
+				root := &gommand.Command{Name: "root"}
+				root.SubCommand(&gommand.Command{
+					Name:         "sub",
+					ArgValidator: gommand.ArgsAny(),
+					Run:          func(ctx *gommand.Context) error { got = ctx.Args(); return nil },
+				})
+				if err := root.ExecuteArgs(context.Background(), tt.args); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !slices.Equal(got, tt.args[1:]) {
+					t.Errorf("Args() = %v, want %v", got, tt.args[1:])
+				}
+			})
+
+			t.Run("WithArgs", func(t *testing.T) {
+				var got []string
+				root := &gommand.Command{Name: "root"}
+				root.SubCommand(&gommand.Command{
+					Name:         "sub",
+					ArgValidator: gommand.ArgsAny(),
+					Run:          func(ctx *gommand.Context) error { got = ctx.Args(); return nil },
+				})
+				if err := root.Execute(gommand.WithArgs(tt.args)); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !slices.Equal(got, tt.args[1:]) {
+					t.Errorf("Args() = %v, want %v", got, tt.args[1:])
+				}
+			})
+		})
+	}
+}
+
+func TestResponseFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "args.txt")
+	if err := os.WriteFile(path, []byte("# includes\n--include a,b\nfile.txt\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("response files expanded when enabled", func(t *testing.T) {
+		fs := flags.NewFlagSet()
+		fs.StringSlice("include", nil, "")
+
+		var got []string
+		cmd := &gommand.Command{
+			Name:         "cmd",
+			FlagSet:      fs,
+			ArgValidator: gommand.ArgsAny(),
+			Run: func(ctx *gommand.Context) error {
+				got = append(ctx.Flags().StringSlice("include"), ctx.Args()...)
+				return nil
+			},
+		}
+		err := cmd.ExecuteArgs(context.Background(), []string{"@" + path}, gommand.WithResponseFiles(1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := []string{"a", "b", "file.txt"}; !slices.Equal(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("response files not expanded by default", func(t *testing.T) {
+		var got []string
+		cmd := &gommand.Command{
+			Name:         "cmd",
+			ArgValidator: gommand.ArgsAny(),
+			Run:          func(ctx *gommand.Context) error { got = ctx.Args(); return nil },
+		}
+		if err := cmd.ExecuteArgs(context.Background(), []string{"@" + path}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(got, []string{"@" + path}) {
+			t.Errorf("got %v, want [@%s]", got, path)
+		}
+	})
+
+	t.Run("missing response file is error", func(t *testing.T) {
+		cmd := &gommand.Command{
+			Name:         "cmd",
+			SilenceHelp:  true,
+			SilenceError: true,
+			Run:          func(ctx *gommand.Context) error { return nil },
+		}
+		err := cmd.ExecuteArgs(context.Background(), []string{"@" + path + ".missing"}, gommand.WithResponseFiles(1))
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected os.ErrNotExist, got %v", err)
+		}
+	})
+}
+
+func TestPrefixMatching(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		want      string
+		wantErr   error
+		exactOnly bool
+	}{
+		{name: "exact match", args: []string{"serve", "a"}, want: "a"},
+		{name: "exact match shadows longer name", args: []string{"server", "a"}, want: "a"},
+		{name: "unique prefix", args: []string{"statu", "a"}, want: "a"},
+		{name: "prefix shared by name and alias", args: []string{"sta", "a"}, want: "a"},
+		{name: "prefix flag", args: []string{"stat", "--verb", "a"}, want: "a verbose"},
+		{name: "ambiguous command", args: []string{"s", "a"}, wantErr: gommand.ErrAmbiguousCommand{}},
+		{name: "ambiguous flag", args: []string{"stat", "--ver", "a"}, wantErr: flags.ErrAmbiguousFlag{}},
+		{name: "disabled", args: []string{"sta", "a"}, exactOnly: true, wantErr: gommand.ErrUnknownCommand{}},
+		{name: "hidden command by full name", args: []string{"statistics", "a"}, want: "a"},
+		{name: "hidden command is not a prefix match", args: []string{"stati", "a"}, wantErr: gommand.ErrUnknownCommand{}},
+		{name: "deprecated command is not a prefix match", args: []string{"sto", "a"}, wantErr: gommand.ErrNoRunner},
+		{name: "hidden flag is not a prefix match", args: []string{"stat", "--verb", "a"}, want: "a verbose"},
+		{name: "empty arg", args: []string{""}, wantErr: gommand.ErrNoRunner},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pfs := flags.NewFlagSet()
+			pfs.Bool("verbose", false, "")
+			pfs.Bool("version-check", false, "")
+			pfs.AddFlag(flags.BoolFlag("verbatim", false, "").Hidden())
+
+			root := &gommand.Command{
+				Name:              "root",
+				ArgValidator:      gommand.ArgsAny(),
+				PrefixMatching:    !tt.exactOnly,
+				PersistentFlagSet: pfs,
+				SilenceHelp:       true,
+				SilenceError:      true,
+			}
+			var ran string
+			record := func(ctx *gommand.Context) error {
+				ran = ctx.Args()[0]
+				if ctx.Flags().Bool("verbose") {
+					ran += " verbose"
+				}
+				return nil
+			}
+			root.SubCommand(
+				&gommand.Command{Name: "server", ArgValidator: gommand.ArgsExact(1), Run: record},
+				&gommand.Command{Name: "serve", ArgValidator: gommand.ArgsExact(1), Run: record},
+				&gommand.Command{Name: "status", Aliases: []string{"stat"}, ArgValidator: gommand.ArgsExact(1), Run: record},
+				&gommand.Command{Name: "statistics", Hidden: true, ArgValidator: gommand.ArgsExact(1), Run: record},
+				&gommand.Command{Name: "stop", Deprecated: "use kill instead", ArgValidator: gommand.ArgsExact(1), Run: record},
+			)
+
+			err := root.ExecuteArgs(context.Background(), tt.args)
+			switch target := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			case gommand.ErrAmbiguousCommand:
+				if !errors.As(err, &target) {
+					t.Fatalf("expected ErrAmbiguousCommand, got %T: %v", err, err)
+				}
+				if want := []string{"serve", "server", "stat", "status"}; !slices.Equal(target.Matches, want) {
+					t.Errorf("Matches = %q, want %q", target.Matches, want)
+				}
+			case gommand.ErrUnknownCommand:
+				if !errors.As(err, &target) {
+					t.Fatalf("expected ErrUnknownCommand, got %T: %v", err, err)
+				}
+			case flags.ErrAmbiguousFlag:
+				if !errors.As(err, &target) {
+					t.Fatalf("expected ErrAmbiguousFlag, got %T: %v", err, err)
+				}
+			default:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected %v, got %v", tt.wantErr, err)
+				}
+			}
+			if ran != tt.want {
+				t.Errorf("got %q, want %q", ran, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	t.Run("unknown subcommand", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", SilenceHelp: true, SilenceError: true}
+		server := &gommand.Command{Name: "server"}
+		server.SubCommand(&gommand.Command{Name: "run", Run: func(ctx *gommand.Context) error { return nil }})
+		root.SubCommand(server)
+
+		err := root.ExecuteArgs(context.Background(), []string{"sever", "run"})
+		var target gommand.ErrUnknownCommand
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrUnknownCommand, got %T: %v", err, err)
+		}
+		if target.Name != "sever" {
+			t.Errorf("Name = %q, want %q", target.Name, "sever")
+		}
+		if !slices.Equal(target.Suggestions, []string{"server"}) {
+			t.Errorf("Suggestions = %v, want [server]", target.Suggestions)
+		}
+		if want := "gommand: unknown command: sever: did you mean server?"; err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("unknown subcommand suggests aliases", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{Name: "status", Aliases: []string{"st"}, Run: func(ctx *gommand.Context) error { return nil }})
+
+		err := root.ExecuteArgs(context.Background(), []string{"stauts"})
+		var target gommand.ErrUnknownCommand
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrUnknownCommand, got %T: %v", err, err)
+		}
+		if !slices.Equal(target.Suggestions, []string{"status"}) {
+			t.Errorf("Suggestions = %v, want [status]", target.Suggestions)
+		}
+	})
+
+	t.Run("hidden, deprecated and short names are not suggested", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", SilenceHelp: true, SilenceError: true}
+		root.SubCommand(
+			&gommand.Command{Name: "server", Run: func(ctx *gommand.Context) error { return nil }},
+			&gommand.Command{Name: "servers", Hidden: true, Run: func(ctx *gommand.Context) error { return nil }},
+			&gommand.Command{Name: "serves", Deprecated: "use server", Run: func(ctx *gommand.Context) error { return nil }},
+			&gommand.Command{Name: "sync", Aliases: []string{"sever-sync"}, DeprecatedAliases: map[string]string{"sever-sync": "use sync"}, Run: func(ctx *gommand.Context) error { return nil }},
+		)
+		err := root.ExecuteArgs(context.Background(), []string{"sever"})
+		var target gommand.ErrUnknownCommand
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrUnknownCommand, got %T: %v", err, err)
+		}
+		if !slices.Equal(target.Suggestions, []string{"server"}) {
+			t.Errorf("Suggestions = %v, want [server]", target.Suggestions)
+		}
+		if err := root.ExecuteArgs(context.Background(), []string{"x"}); errors.As(err, &target) {
+			t.Errorf("expected no suggestions for a single character, got %v", target.Suggestions)
+		}
+	})
+
+	t.Run("unknown long flag", func(t *testing.T) {
+		fs := flags.NewFlagSet()
+		fs.Int("port", 0, "")
+		fs.String("protocol", "", "")
+		cmd := &gommand.Command{Name: "run", FlagSet: fs, SilenceHelp: true, SilenceError: true, Run: func(ctx *gommand.Context) error { return nil }}
+
+		err := cmd.ExecuteArgs(context.Background(), []string{"--prot", "80"})
+		var target gommand.ErrUnknownFlag
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrUnknownFlag, got %T: %v", err, err)
+		}
+		if target.Flag != "--prot" {
+			t.Errorf("Flag = %q, want %q", target.Flag, "--prot")
+		}
+		if want := []string{"--port", "--protocol"}; !slices.Equal(target.Suggestions, want) {
+			t.Errorf("Suggestions = %v, want %v", target.Suggestions, want)
+		}
+		if want := "gommand: unknown flag: --prot: did you mean --port or --protocol?"; err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("unknown short flag", func(t *testing.T) {
+		fs := flags.NewFlagSet()
+		fs.Int("port", 0, "")
+		cmd := &gommand.Command{Name: "run", FlagSet: fs, SilenceHelp: true, SilenceError: true, Run: func(ctx *gommand.Context) error { return nil }}
+
+		err := cmd.ExecuteArgs(context.Background(), []string{"-z"})
+		var target gommand.ErrUnknownFlag
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrUnknownFlag, got %T: %v", err, err)
+		}
+		if target.Flag != "-z" || len(target.Suggestions) != 0 {
+			t.Errorf("got %+v, want -z with no suggestions", target)
+		}
+	})
+}
+
+func TestHidden(t *testing.T) {
+	t.Run("hidden entries omitted from help", func(t *testing.T) {
+		root := &gommand.Command{
+			Name: "root",
+			PersistentFlagSet: flags.NewFlagSet().AddFlags(
+				flags.BoolFlag("legacy", false, "legacy flag").Hidden(),
+				flags.BoolFlag("visible", false, "visible flag"),
+			),
+		}
+		root.SubCommand(
+			&gommand.Command{Name: "debug", Usage: "debug things", Hidden: true, Run: func(ctx *gommand.Context) error { return nil }},
+			&gommand.Command{Name: "status", Usage: "show status", Run: func(ctx *gommand.Context) error { return nil }},
+		)
+
+		var buf bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"--help"}, gommand.WithStdout(&buf)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		help := buf.String()
+		for _, hidden := range []string{"debug", "legacy"} {
+			if strings.Contains(help, hidden) {
+				t.Errorf("help text contains hidden entry %q:\n%s", hidden, help)
+			}
+		}
+		for _, visible := range []string{"status", "visible"} {
+			if !strings.Contains(help, visible) {
+				t.Errorf("help text missing %q:\n%s", visible, help)
+			}
+		}
+	})
+
+	t.Run("hidden entries are still parsed", func(t *testing.T) {
+		var ran string
+		root := &gommand.Command{
+			Name:              "root",
+			PersistentFlagSet: flags.NewFlagSet().AddFlags(flags.BoolFlag("legacy", false, "legacy flag").Hidden()),
+		}
+		root.SubCommand(&gommand.Command{Name: "debug", Hidden: true, Run: func(ctx *gommand.Context) error {
+			ran = "debug"
+			if ctx.Flags().Bool("legacy") {
+				ran += " legacy"
+			}
+			return nil
+		}})
+
+		if err := root.ExecuteArgs(context.Background(), []string{"debug", "--legacy"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ran != "debug legacy" {
+			t.Errorf("got %q, want %q", ran, "debug legacy")
+		}
+	})
+
+	t.Run("hidden commands are not suggested", func(t *testing.T) {
+		root := &gommand.Command{Name: "root", SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{Name: "debug", Hidden: true, Run: func(ctx *gommand.Context) error { return nil }})
+
+		err := root.ExecuteArgs(context.Background(), []string{"debg"})
+		var target gommand.ErrUnknownCommand
+		if errors.As(err, &target) {
+			t.Errorf("expected no suggestions for hidden command, got %v", err)
+		}
+	})
+}
+
+func TestDeprecation(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		want       string
+		wantStderr string
+	}{
+		{
+			name: "no warnings",
+			args: []string{"server", "run", "--listen", ":80"},
+			want: ":80",
+		},
+		{
+			name:       "deprecated alias forwards to command",
+			args:       []string{"server", "start"},
+			wantStderr: "Warning: command alias \"start\" is deprecated: use \"server run\" instead\n",
+		},
+		{
+			name:       "deprecated command",
+			args:       []string{"server", "old"},
+			want:       "old",
+			wantStderr: "Warning: command \"mytool server old\" is deprecated: it will be removed in v2\n",
+		},
+		{
+			name:       "deprecated flag forwards to replacement and warns once",
+			args:       []string{"server", "run", "--addr", ":80", "--addr=:8080"},
+			want:       ":8080",
+			wantStderr: "Warning: flag --addr is deprecated: use --listen instead\n",
+		},
+		{
+			name:       "deprecated short flag",
+			args:       []string{"server", "run", "-l"},
+			wantStderr: "Warning: flag --legacy is deprecated: it does nothing\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				got    string
+				stderr bytes.Buffer
+			)
+			listen := flags.StringFlag("listen", "", "listen address")
+			root := &gommand.Command{
+				Name: "mytool",
+				PersistentFlagSet: flags.NewFlagSet().AddFlags(
+					listen,
+					flags.AliasFlag("addr", listen).Deprecated("use --listen instead"),
+					flags.BoolFlagS("legacy", 'l', false, "legacy").Deprecated("it does nothing"),
+				),
+			}
+			server := &gommand.Command{Name: "server"}
+			server.SubCommand(
+				&gommand.Command{
+					Name:              "run",
+					DeprecatedAliases: map[string]string{"start": `use "server run" instead`},
+					Run:               func(ctx *gommand.Context) error { got = ctx.Flags().String("listen"); return nil },
+				},
+				&gommand.Command{
+					Name:       "old",
+					Deprecated: "it will be removed in v2",
+					Run:        func(ctx *gommand.Context) error { got = "old"; return nil },
+				},
+			)
+			root.SubCommand(server)
+
+			if err := root.ExecuteArgs(context.Background(), tt.args, gommand.WithStderr(&stderr)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if stderr.String() != tt.wantStderr {
+				t.Errorf("stderr = %q, want %q", stderr.String(), tt.wantStderr)
+			}
+		})
+	}
+
+	t.Run("deprecated entries hidden from help", func(t *testing.T) {
+		listen := flags.StringFlag("listen", "", "listen address")
+		root := &gommand.Command{
+			Name: "mytool",
+			PersistentFlagSet: flags.NewFlagSet().AddFlags(
+				listen,
+				flags.AliasFlag("addr", listen).Deprecated("use --listen instead"),
+				flags.BoolFlag("legacy", false, "legacy").Deprecated("it does nothing"),
+			),
+		}
+		server := &gommand.Command{Name: "server"}
+		server.SubCommand(
+			&gommand.Command{
+				Name:              "run",
+				Usage:             "run the server",
+				DeprecatedAliases: map[string]string{"start": `use "server run" instead`},
+				Run:               func(ctx *gommand.Context) error { return nil },
+			},
+			&gommand.Command{
+				Name:       "old",
+				Usage:      "the old way",
+				Deprecated: "it will be removed in v2",
+				Run:        func(ctx *gommand.Context) error { return nil },
+			},
+		)
+		root.SubCommand(server)
+
+		var buf bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"server", "--help"}, gommand.WithStdout(&buf)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		help := buf.String()
+		for _, hidden := range []string{"old", "start", "--addr", "--legacy"} {
+			if strings.Contains(help, hidden) {
+				t.Errorf("help text contains deprecated entry %q:\n%s", hidden, help)
+			}
+		}
+		if !strings.Contains(help, "run the server") {
+			t.Errorf("help text missing run command:\n%s", help)
+		}
+	})
+}
+
+func TestCommandGroups(t *testing.T) {
+	tests := []struct {
+		name             string
+		declarationOrder bool
+		want             string
+	}{
+		{
+			name: "sorted",
+			want: "Management Commands:\n" +
+				"  network  manage networks\n" +
+				"  volume   manage volumes\n" +
+				"\n" +
+				"Debug Commands:\n" +
+				"  trace    trace calls\n" +
+				"\n" +
+				"Additional Commands:\n" +
+				"  version  print the version\n",
+		},
+		{
+			name:             "declaration order",
+			declarationOrder: true,
+			want: "Management Commands:\n" +
+				"  volume   manage volumes\n" +
+				"  network  manage networks\n" +
+				"\n" +
+				"Debug Commands:\n" +
+				"  trace    trace calls\n" +
+				"\n" +
+				"Additional Commands:\n" +
+				"  version  print the version\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			noop := func(*gommand.Context) error { return nil }
+			root := &gommand.Command{
+				Name: "root",
+				Groups: []gommand.Group{
+					{ID: "manage", Title: "Management Commands"},
+					{ID: "debug", Title: "Debug Commands"},
+				},
+				DeclarationOrder: tt.declarationOrder,
+			}
+			root.SubCommand(
+				&gommand.Command{Name: "volume", Usage: "manage volumes", Group: "manage", Run: noop},
+				&gommand.Command{Name: "trace", Usage: "trace calls", Group: "debug", Run: noop},
+				&gommand.Command{Name: "network", Usage: "manage networks", Group: "manage", Run: noop},
+				&gommand.Command{Name: "version", Usage: "print the version", Run: noop},
+			)
+
+			var buf bytes.Buffer
+			if err := root.ExecuteArgs(context.Background(), []string{"--help"}, gommand.WithStdout(&buf)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if help := buf.String(); !strings.Contains(help, tt.want) {
+				t.Errorf("help text missing groups, want:\n%s\ngot:\n%s", tt.want, help)
+			}
+		})
+	}
+
+	t.Run("undefined group", func(t *testing.T) {
+		root := &gommand.Command{Name: "root", Groups: []gommand.Group{{ID: "manage", Title: "Management Commands"}}}
+		root.SubCommand(&gommand.Command{Name: "logs", Group: "observe"})
+		var target gommand.ErrConflict
+		if err := root.Validate(); !errors.As(err, &target) {
+			t.Errorf("expected ErrConflict, got %v", err)
+		}
+	})
+}
+
 func TestContextCancel(t *testing.T) {
 	defer overwriteArgs([]string{})()
 	cmd := &gommand.Command{
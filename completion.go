@@ -0,0 +1,609 @@
+package gommand
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jimmykodes/gommand/flags"
+	"github.com/jimmykodes/gommand/internal/lexer"
+)
+
+// Shells are the shells completion scripts can be generated for
+var Shells = []string{"bash", "zsh", "fish"}
+
+// GenCompletion writes a completion script for the command tree rooted at c to w.
+// shell is one of Shells.
+//
+// The script completes the names and aliases of subcommands, and the long and short flags
+// of each command, including those inherited from the PersistentFlagSets of its parents.
+// Hidden and deprecated commands and flags are not completed.
+//
+// Args of commands with a Complete callback, and flag values, are completed by calling the
+// hidden __complete command registered by WithCompletionCommand, which runs the callbacks.
+// Without it, the shell falls back to completing file names.
+//
+// ie: mytool completion bash > /etc/bash_completion.d/mytool
+func (c *Command) GenCompletion(w io.Writer, shell string) error {
+	var (
+		script string
+		name   = c.name()
+		fn     = "_" + nonIdentChars.ReplaceAllString(name, "_")
+		nodes  = c.completionNodes()
+	)
+	switch shell {
+	case "bash":
+		script = bashCompletion(name, fn, nodes)
+	case "zsh":
+		script = zshCompletion(name, fn, nodes)
+	case "fish":
+		script = fishCompletion(name, "_"+fn, nodes)
+	default:
+		return ErrUnsupportedShell{Shell: shell}
+	}
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// newCompletionCommand returns the command registered by WithCompletionCommand
+func newCompletionCommand() *Command {
+	return &Command{
+		Name:         "completion {" + strings.Join(Shells, "|") + "}",
+		Usage:        "generate a shell completion script",
+		ArgValidator: ArgsExact(1),
+		Run: func(ctx *Context) error {
+			return ctx.cmd.parent.GenCompletion(ctx.Stdout(), ctx.Arg(0))
+		},
+	}
+}
+
+// newCompleteCommand returns the hidden command registered by WithCompletionCommand that the
+// completion scripts call to complete values, as
+//
+//	mytool __complete -- server run --port ""
+//
+// with the words on the command line, the last being the partial word to complete. It prints
+// one candidate per line, followed by the flags.CompletionDirective as :<directive>
+func newCompleteCommand() *Command {
+	return &Command{
+		Name:    "__complete",
+		Hidden:  true,
+		noHooks: true,
+		Run: func(ctx *Context) error {
+			words := ctx.PassthroughArgs()
+			var toComplete string
+			if len(words) > 0 {
+				words, toComplete = words[:len(words)-1], words[len(words)-1]
+			}
+			candidates, directive := ctx.cmd.parent.complete(ctx, words, toComplete)
+			for _, candidate := range candidates {
+				_, _ = fmt.Fprintln(ctx.Stdout(), candidate)
+			}
+			_, err := fmt.Fprintf(ctx.Stdout(), ":%d\n", directive)
+			return err
+		},
+	}
+}
+
+// complete parses words against the command tree rooted at c, as they would be when executing
+// it, and returns the candidates for the word following them.
+func (c *Command) complete(parent *Context, words []string, toComplete string) ([]string, flags.CompletionDirective) {
+	var (
+		ctx        = &Context{Context: parent.Context, stdin: parent.stdin, stderr: parent.stderr}
+		l          = lexer.New(words)
+		cmd        *Command
+		fs         *flags.FlagSet
+		pending    flags.Flag
+		terminated bool
+	)
+	enter := func(next *Command) {
+		cmd = next
+		ctx.cmd = next
+		ctx.addPersistentFlags(next.PersistentFlagSet)
+		if next.PrefixMatching {
+			ctx.prefixMatching = true
+		}
+		fs = flags.NewFlagSet(flags.WithHelpFlag()).AddFlagSet(ctx.persistentFlags()).AddFlagSet(next.FlagSet)
+		ctx.flagGetter = flags.NewFlagGetter(fs)
+	}
+	enter(c)
+
+	for token := l.Read(); token != nil; token = l.Read() {
+		pending = nil
+		switch token.Type {
+		case lexer.TerminatorType:
+			terminated = true
+		case lexer.ValueType:
+			if terminated {
+				ctx.passthrough = append(ctx.passthrough, token.Value)
+				continue
+			}
+			if len(ctx.args) == 0 {
+				if next, _ := cmd.commands.lookup(token.Value, ctx.prefixMatching); next != nil {
+					enter(next)
+					continue
+				}
+			}
+			ctx.args = append(ctx.args, token.Value)
+		case lexer.LongFlagType:
+			f := fs.FromName(token.Name)
+			if f == nil && ctx.prefixMatching {
+				f, _ = fs.FromPrefix(token.Name)
+			}
+			if f != nil {
+				pending = completeParseFlag(l, f, token.Value)
+			}
+		case lexer.ShortFlagType:
+			if f := fs.FromShort(rune(token.Name[0])); f != nil {
+				pending = completeParseFlag(l, f, token.Value)
+			}
+		case lexer.MultiFlagType:
+			for i, chr := range token.Name {
+				f := fs.FromShort(chr)
+				if f == nil {
+					break
+				}
+				if !takesValue(f) {
+					completeParseFlag(l, f, "")
+					continue
+				}
+				value := token.Name[i+utf8.RuneLen(chr):]
+				if token.Value != "" {
+					value += "=" + token.Value
+				}
+				pending = completeParseFlag(l, f, strings.TrimPrefix(value, "="))
+				break
+			}
+		}
+	}
+
+	if pending != nil {
+		return completeFlag(ctx, pending, toComplete)
+	}
+
+	if !terminated && strings.HasPrefix(toComplete, "-") {
+		if name, value, ok := strings.Cut(toComplete, "="); ok && strings.HasPrefix(name, "--") {
+			f := fs.FromName(strings.TrimPrefix(name, "--"))
+			if f == nil {
+				return nil, flags.CompleteNoFile
+			}
+			candidates, directive := completeFlag(ctx, f, value)
+			for i, candidate := range candidates {
+				candidates[i] = name + "=" + candidate
+			}
+			return candidates, directive
+		}
+		var candidates []string
+		for _, f := range visibleFlags(fs) {
+			for _, word := range flagWords(f) {
+				if strings.HasPrefix(word, toComplete) {
+					candidates = append(candidates, word+"\t"+f.Usage())
+				}
+			}
+		}
+		return candidates, flags.CompleteNoFile
+	}
+
+	var (
+		candidates []string
+		directive  flags.CompletionDirective
+	)
+	if !terminated && len(ctx.args) == 0 && cmd.hasSubCommands() {
+		for _, sub := range cmd.HelpData().Commands {
+			for _, name := range sub.names() {
+				if strings.HasPrefix(name, toComplete) {
+					candidates = append(candidates, name+"\t"+sub.Usage)
+				}
+			}
+		}
+		directive = flags.CompleteNoFile
+	}
+	if cmd.Complete != nil {
+		more, d := cmd.Complete(ctx, toComplete)
+		candidates = append(candidates, more...)
+		directive = d
+	} else if arg, ok := cmd.argAt(len(ctx.args)); ok && flags.Completer(arg.Flag) != nil {
+		more, d := completeFlag(ctx, arg.Flag, toComplete)
+		candidates = append(candidates, more...)
+		directive = d
+	}
+	return candidates, directive
+}
+
+// completeParseFlag sets f from the words being completed as it would be set when executing.
+// If the value of f is the word being completed, f is returned, otherwise nil.
+func completeParseFlag(l *lexer.Lexer, f flags.Flag, value string) flags.Flag {
+	switch {
+	case value != "":
+		_ = f.Set(value)
+	case f.Type() == flags.BoolFlagType:
+		_ = f.Set("true")
+	case f.Type() == flags.CountFlagType:
+		_ = flags.Increment(f)
+	default:
+		if implicit, ok := flags.ImplicitValue(f); ok {
+			_ = f.Set(implicit)
+			return nil
+		}
+		next := l.Peek()
+		if next == nil {
+			return f
+		}
+		if next.Type == lexer.ValueType {
+			_ = f.Set(l.Read().Value)
+		}
+	}
+	return nil
+}
+
+// completeFlag returns the candidates for the value of f
+func completeFlag(ctx *Context, f flags.Flag, toComplete string) ([]string, flags.CompletionDirective) {
+	fn := flags.Completer(f)
+	if fn == nil {
+		return nil, flags.CompleteDefault
+	}
+	return fn(ctx, toComplete)
+}
+
+var nonIdentChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// completionNode is a command in the tree, as seen by a completion script
+type completionNode struct {
+	// path is the path of the command using the names of its parents, never their aliases
+	path     string
+	commands []HelpCommand
+	flags    []flags.Flag
+	// dynamic is true if the command completes its args with a Complete callback, or
+	// the completion functions of its Args
+	dynamic bool
+}
+
+func (c *Command) completionNodes() []completionNode {
+	data := c.HelpData()
+	nodes := []completionNode{{
+		path:     data.Path,
+		commands: data.Commands,
+		flags:    slices.Concat(data.Flags, data.InheritedFlags),
+		dynamic:  c.Complete != nil || slices.ContainsFunc(c.Args, func(a Arg) bool { return flags.Completer(a.Flag) != nil }),
+	}}
+	for _, sub := range data.Commands {
+		nodes = append(nodes, sub.Command.completionNodes()...)
+	}
+	return nodes
+}
+
+// names returns the names the command can be called by, relative to the node
+func (hc HelpCommand) names() []string {
+	return append([]string{hc.Name}, hc.Aliases...)
+}
+
+// flagWords returns the words the flag can be passed as. ie: --cache --no-cache -c
+func flagWords(f flags.Flag) []string {
+	words := []string{"--" + f.Name()}
+	if flags.Negatable(f) {
+		words = append(words, "--no-"+f.Name())
+	}
+	if f.Short() != 0 {
+		words = append(words, "-"+string(f.Short()))
+	}
+	return words
+}
+
+// takesValue reports whether the flag consumes the next argument as its value
+func takesValue(f flags.Flag) bool {
+	if _, ok := flags.ImplicitValue(f); ok {
+		return false
+	}
+	return f.Type() != flags.BoolFlagType && f.Type() != flags.CountFlagType
+}
+
+// completionWords returns the subcommands and flags that can follow the node
+func (n completionNode) completionWords() (commands, flagNames []string) {
+	for _, cmd := range n.commands {
+		commands = append(commands, cmd.names()...)
+	}
+	for _, f := range n.flags {
+		flagNames = append(flagNames, flagWords(f)...)
+	}
+	return commands, flagNames
+}
+
+// shRoutes returns the case statement entries, shared by bash and zsh, that track the
+// command being completed as the words typed so far are walked
+func shRoutes(nodes []completionNode, pathVar, skip string) string {
+	var sb strings.Builder
+	for _, n := range nodes {
+		for _, cmd := range n.commands {
+			patterns := make([]string, 0, len(cmd.names()))
+			for _, name := range cmd.names() {
+				patterns = append(patterns, shQuote(n.path+" "+name))
+			}
+			_, _ = fmt.Fprintf(&sb, "            %s) %s=%s ;;\n", strings.Join(patterns, "|"), pathVar, shQuote(n.path+" "+cmd.Name))
+		}
+		var patterns []string
+		for _, f := range n.flags {
+			if !takesValue(f) {
+				continue
+			}
+			for _, word := range flagWords(f) {
+				patterns = append(patterns, shQuote(n.path+" "+word))
+			}
+		}
+		if len(patterns) > 0 {
+			_, _ = fmt.Fprintf(&sb, "            %s) %s ;;\n", strings.Join(patterns, "|"), skip)
+		}
+	}
+	return sb.String()
+}
+
+func bashCompletion(name, fn string, nodes []completionNode) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "# bash completion for %s\n\n", name)
+	_, _ = fmt.Fprintf(&sb, bashDynamic, fn, flags.CompleteNoFile, flags.CompleteFilterExt, flags.CompleteFilterDirs)
+	_, _ = fmt.Fprintf(&sb, "%s() {\n", fn)
+	sb.WriteString("    local cur word path i commands flags dynamic=0\n")
+	sb.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	_, _ = fmt.Fprintf(&sb, "    path=%s\n", shQuote(name))
+	sb.WriteString("    for ((i = 1; i < COMP_CWORD; i++)); do\n")
+	sb.WriteString("        word=\"${COMP_WORDS[i]}\"\n")
+	sb.WriteString("        case \"$path $word\" in\n")
+	sb.WriteString(shRoutes(nodes, "path", "((i++))"))
+	sb.WriteString("        esac\n")
+	sb.WriteString("    done\n\n")
+	sb.WriteString("    case \"$path\" in\n")
+	for _, n := range nodes {
+		commands, flagNames := n.completionWords()
+		_, _ = fmt.Fprintf(&sb, "        %s) commands=%s; flags=%s%s ;;\n",
+			shQuote(n.path), shQuote(strings.Join(commands, " ")), shQuote(strings.Join(flagNames, " ")), dynamicVar(n))
+	}
+	sb.WriteString("    esac\n\n")
+	sb.WriteString("    # the last word skipped was a flag, so the current word is its value\n")
+	sb.WriteString("    if ((i > COMP_CWORD)); then\n")
+	_, _ = fmt.Fprintf(&sb, "        %s_dynamic\n", fn)
+	sb.WriteString("    elif [[ \"$cur\" == -* ]]; then\n")
+	sb.WriteString("        COMPREPLY=($(compgen -W \"$flags\" -- \"$cur\"))\n")
+	sb.WriteString("    elif ((dynamic)); then\n")
+	_, _ = fmt.Fprintf(&sb, "        %s_dynamic\n", fn)
+	sb.WriteString("    else\n")
+	sb.WriteString("        COMPREPLY=($(compgen -W \"$commands\" -- \"$cur\"))\n")
+	sb.WriteString("    fi\n")
+	sb.WriteString("}\n\n")
+	_, _ = fmt.Fprintf(&sb, "complete -F %s %s\n", fn, name)
+	return sb.String()
+}
+
+func zshCompletion(name, fn string, nodes []completionNode) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "#compdef %s\n\n", name)
+	_, _ = fmt.Fprintf(&sb, zshDynamic, fn, flags.CompleteNoFile, flags.CompleteFilterExt, flags.CompleteFilterDirs)
+	_, _ = fmt.Fprintf(&sb, "%s() {\n", fn)
+	// path is tied to $PATH in zsh, so cmdpath is used instead
+	sb.WriteString("    local cmdpath word i dynamic=0\n")
+	sb.WriteString("    local -a commands flags\n")
+	_, _ = fmt.Fprintf(&sb, "    cmdpath=%s\n", shQuote(name))
+	sb.WriteString("    for ((i = 2; i < CURRENT; i++)); do\n")
+	sb.WriteString("        word=\"${words[i]}\"\n")
+	sb.WriteString("        case \"$cmdpath $word\" in\n")
+	sb.WriteString(shRoutes(nodes, "cmdpath", "((i++))"))
+	sb.WriteString("        esac\n")
+	sb.WriteString("    done\n\n")
+	sb.WriteString("    case \"$cmdpath\" in\n")
+	for _, n := range nodes {
+		commands, flagNames := n.completionWords()
+		_, _ = fmt.Fprintf(&sb, "        %s) commands=(%s); flags=(%s)%s ;;\n",
+			shQuote(n.path), shQuoteAll(commands), shQuoteAll(flagNames), dynamicVar(n))
+	}
+	sb.WriteString("    esac\n\n")
+	sb.WriteString("    # the last word skipped was a flag, so the current word is its value\n")
+	sb.WriteString("    if ((i > CURRENT)); then\n")
+	_, _ = fmt.Fprintf(&sb, "        %s_dynamic\n", fn)
+	sb.WriteString("    elif [[ \"${words[CURRENT]}\" == -* ]]; then\n")
+	sb.WriteString("        compadd -- \"${flags[@]}\"\n")
+	sb.WriteString("    elif ((dynamic)); then\n")
+	_, _ = fmt.Fprintf(&sb, "        %s_dynamic\n", fn)
+	sb.WriteString("    else\n")
+	sb.WriteString("        compadd -- \"${commands[@]}\"\n")
+	sb.WriteString("    fi\n")
+	sb.WriteString("}\n\n")
+	_, _ = fmt.Fprintf(&sb, "if [ \"$funcstack[1]\" = %s ]; then\n", shQuote(fn))
+	_, _ = fmt.Fprintf(&sb, "    %s \"$@\"\n", fn)
+	sb.WriteString("else\n")
+	_, _ = fmt.Fprintf(&sb, "    compdef %s %s\n", fn, name)
+	sb.WriteString("fi\n")
+	return sb.String()
+}
+
+func fishCompletion(name, fn string, nodes []completionNode) string {
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "# fish completion for %s\n\n", name)
+
+	_, _ = fmt.Fprintf(&sb, "function %s_path\n", fn)
+	_, _ = fmt.Fprintf(&sb, "    set -l path %s\n", fishQuote(name))
+	sb.WriteString("    set -l skip 0\n")
+	sb.WriteString("    set -l words (commandline -opc)\n")
+	sb.WriteString("    set -e words[1]\n")
+	sb.WriteString("    for word in $words\n")
+	sb.WriteString("        if test $skip -eq 1\n")
+	sb.WriteString("            set skip 0\n")
+	sb.WriteString("            continue\n")
+	sb.WriteString("        end\n")
+	sb.WriteString("        switch \"$path $word\"\n")
+	for _, n := range nodes {
+		for _, cmd := range n.commands {
+			patterns := make([]string, 0, len(cmd.names()))
+			for _, name := range cmd.names() {
+				patterns = append(patterns, fishQuote(n.path+" "+name))
+			}
+			_, _ = fmt.Fprintf(&sb, "            case %s\n", strings.Join(patterns, " "))
+			_, _ = fmt.Fprintf(&sb, "                set path %s\n", fishQuote(n.path+" "+cmd.Name))
+		}
+		var patterns []string
+		for _, f := range n.flags {
+			if !takesValue(f) {
+				continue
+			}
+			for _, word := range flagWords(f) {
+				patterns = append(patterns, fishQuote(n.path+" "+word))
+			}
+		}
+		if len(patterns) > 0 {
+			_, _ = fmt.Fprintf(&sb, "            case %s\n", strings.Join(patterns, " "))
+			sb.WriteString("                set skip 1\n")
+		}
+	}
+	sb.WriteString("        end\n")
+	sb.WriteString("    end\n")
+	sb.WriteString("    # the last word was a flag, so the current word is its value\n")
+	sb.WriteString("    if test $skip -eq 1\n")
+	sb.WriteString("        set path \"$path --\"\n")
+	sb.WriteString("    end\n")
+	sb.WriteString("    echo $path\n")
+	sb.WriteString("end\n\n")
+
+	_, _ = fmt.Fprintf(&sb, "function %s_at\n", fn)
+	_, _ = fmt.Fprintf(&sb, "    test (%s_path) = \"$argv\"\n", fn)
+	sb.WriteString("end\n\n")
+
+	var dynamic []string
+	for _, n := range nodes {
+		if n.dynamic {
+			dynamic = append(dynamic, fishQuote(n.path))
+		}
+	}
+	_, _ = fmt.Fprintf(&sb, "function %s_dynamic_at\n", fn)
+	_, _ = fmt.Fprintf(&sb, "    set -l path (%s_path)\n", fn)
+	sb.WriteString("    string match -q -- '* --' $path; and return 0\n")
+	sb.WriteString("    string match -q -- '-*' (commandline -ct); and return 1\n")
+	_, _ = fmt.Fprintf(&sb, "    contains -- $path %s\n", strings.Join(dynamic, " "))
+	sb.WriteString("end\n\n")
+
+	_, _ = fmt.Fprintf(&sb, fishDynamic, fn, flags.CompleteNoFile, flags.CompleteFilterExt, flags.CompleteFilterDirs)
+
+	_, _ = fmt.Fprintf(&sb, "complete -c %s -f\n", name)
+	_, _ = fmt.Fprintf(&sb, "complete -c %s -n %s -a %s\n", name, fishQuote(fn+"_dynamic_at"), fishQuote("("+fn+"_dynamic)"))
+	for _, n := range nodes {
+		cond := fishQuote(fn + "_at " + n.path)
+		for _, cmd := range n.commands {
+			for _, word := range cmd.names() {
+				_, _ = fmt.Fprintf(&sb, "complete -c %s -n %s -a %s -d %s\n", name, cond, fishQuote(word), fishQuote(cmd.Usage))
+			}
+		}
+		for _, f := range n.flags {
+			line := fmt.Sprintf("complete -c %s -n %s -l %s", name, cond, fishQuote(f.Name()))
+			if f.Short() != 0 {
+				line += " -s " + fishQuote(string(f.Short()))
+			}
+			if takesValue(f) {
+				line += " -r"
+			}
+			sb.WriteString(line + " -d " + fishQuote(f.Usage()) + "\n")
+			if flags.Negatable(f) {
+				_, _ = fmt.Fprintf(&sb, "complete -c %s -n %s -l %s -d %s\n", name, cond, fishQuote("no-"+f.Name()), fishQuote(f.Usage()))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// dynamicVar sets the dynamic variable of the bash and zsh scripts for nodes with a Complete callback
+func dynamicVar(n completionNode) string {
+	if n.dynamic {
+		return "; dynamic=1"
+	}
+	return ""
+}
+
+// bashDynamic is the bash function that completes the current word using the __complete command.
+// It is formatted with the name of the completion function and the directives it handles.
+const bashDynamic = `%[1]s_dynamic() {
+    local cur out directive line
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    out="$("${COMP_WORDS[0]}" __complete -- "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null)" || return
+    directive="${out##*:}"
+    out="${out%%:*}"
+    COMPREPLY=()
+    if ((directive & %[4]d)); then
+        COMPREPLY=($(compgen -d -- "$cur"))
+    elif ((directive & %[3]d)); then
+        while IFS= read -r line; do
+            [[ -n "$line" ]] && COMPREPLY+=($(compgen -f -X "!*.$line" -- "$cur"))
+        done <<< "$out"
+        COMPREPLY+=($(compgen -d -- "$cur"))
+    else
+        while IFS= read -r line; do
+            line="${line%%%%$'\t'*}"
+            [[ -n "$line" && "$line" == "$cur"* ]] && COMPREPLY+=("$line")
+        done <<< "$out"
+        if ((${#COMPREPLY[@]} == 0 && !(directive & %[2]d))); then
+            COMPREPLY=($(compgen -f -- "$cur"))
+        fi
+    fi
+}
+
+`
+
+// zshDynamic is the zsh function that completes the current word using the __complete command.
+// It is formatted with the name of the completion function and the directives it handles.
+const zshDynamic = `%[1]s_dynamic() {
+    local -a out
+    local directive
+    out=("${(@f)$("${words[1]}" __complete -- "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    [[ "${out[-1]}" == :* ]] || return
+    directive="${out[-1]#:}"
+    out=("${(@)${(@)out[1,-2]}%%%%$'\t'*}")
+    if ((directive & %[4]d)); then
+        _files -/
+    elif ((directive & %[3]d)); then
+        _files -g "*.(${(j:|:)out})"
+    elif ((${#out})); then
+        compadd -- "${out[@]}"
+    elif ((!(directive & %[2]d))); then
+        _files
+    fi
+}
+
+`
+
+// fishDynamic is the fish function that completes the current word using the __complete command.
+// It is formatted with the name of the completion function and the directives it handles.
+const fishDynamic = `function %[1]s_dynamic
+    set -l words (commandline -opc)
+    set -l out ($words[1] __complete -- $words[2..-1] (commandline -ct) 2>/dev/null)
+    string match -q -- ':*' $out[-1]; or return
+    set -l directive (string sub -s 2 -- $out[-1])
+    set -e out[-1]
+    if test (math "bitand($directive, %[4]d)") -ne 0
+        __fish_complete_directories (commandline -ct)
+    else if test (math "bitand($directive, %[3]d)") -ne 0
+        for ext in $out
+            __fish_complete_suffix .$ext
+        end
+    else if test (count $out) -gt 0
+        printf '%%s\n' $out
+    else if test (math "bitand($directive, %[2]d)") -eq 0
+        __fish_complete_path (commandline -ct)
+    end
+end
+
+`
+
+// shQuote quotes s to be used as a single word in bash or zsh
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func shQuoteAll(words []string) string {
+	quoted := make([]string, len(words))
+	for i, word := range words {
+		quoted[i] = shQuote(word)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// fishQuote quotes s to be used as a single word in fish
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
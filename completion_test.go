@@ -0,0 +1,255 @@
+package gommand_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
+)
+
+func newCompletionTree() *gommand.Command {
+	noop := func(*gommand.Context) error { return nil }
+	root := &gommand.Command{
+		Name: "mytool",
+		PersistentFlagSet: flags.NewFlagSet().AddFlags(
+			flags.StringFlagS("log-level", 'l', "info", "log level"),
+			flags.BoolFlag("trace", false, "trace calls").Hidden(),
+		),
+	}
+	server := &gommand.Command{Name: "server", Usage: "manage servers", Aliases: []string{"srv"}}
+	server.SubCommand(&gommand.Command{
+		Name:    "run",
+		Usage:   "run a server",
+		FlagSet: flags.NewFlagSet().AddFlags(flags.IntFlagS("port", 'p', 8080, "port"), flags.BoolFlag("cache", true, "use cache")),
+		Run:     noop,
+	})
+	root.SubCommand(
+		server,
+		&gommand.Command{Name: "debug", Hidden: true, Run: noop},
+		&gommand.Command{Name: "start", Deprecated: "use server run", Run: noop},
+	)
+	return root
+}
+
+func TestGenCompletion(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{
+			shell: "bash",
+			want: []string{
+				"complete -F _mytool mytool\n",
+				`'mytool server'|'mytool srv') path='mytool server' ;;`,
+				`'mytool server run --port'|'mytool server run -p'|'mytool server run --log-level'|'mytool server run -l') ((i++)) ;;`,
+				`'mytool server run') commands=''; flags='--cache --no-cache --help -h --port -p --log-level -l' ;;`,
+			},
+		},
+		{
+			shell: "zsh",
+			want: []string{
+				"#compdef mytool\n",
+				`'mytool server'|'mytool srv') cmdpath='mytool server' ;;`,
+				`'mytool') commands=('server' 'srv'); flags=('--help' '-h' '--log-level' '-l') ;;`,
+			},
+		},
+		{
+			shell: "fish",
+			want: []string{
+				"case 'mytool server' 'mytool srv'\n                set path 'mytool server'\n",
+				"complete -c mytool -n '__mytool_at mytool' -a 'srv' -d 'manage servers'\n",
+				"complete -c mytool -n '__mytool_at mytool server run' -l 'port' -s 'p' -r -d 'port'\n",
+				"complete -c mytool -n '__mytool_at mytool server run' -l 'no-cache' -d 'use cache'\n",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := newCompletionTree().GenCompletion(&buf, tt.shell); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			script := buf.String()
+			for _, want := range tt.want {
+				if !strings.Contains(script, want) {
+					t.Errorf("script missing %q:\n%s", want, script)
+				}
+			}
+			for _, hidden := range []string{"debug", "start", "trace"} {
+				if strings.Contains(script, hidden) {
+					t.Errorf("script contains hidden entry %q:\n%s", hidden, script)
+				}
+			}
+		})
+	}
+
+	t.Run("unsupported shell", func(t *testing.T) {
+		var target gommand.ErrUnsupportedShell
+		if err := newCompletionTree().GenCompletion(&bytes.Buffer{}, "tcsh"); !errors.As(err, &target) {
+			t.Errorf("expected ErrUnsupportedShell, got %v", err)
+		}
+	})
+}
+
+func TestBashCompletion(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+	var buf bytes.Buffer
+	if err := newCompletionTree().GenCompletion(&buf, "bash"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	script := filepath.Join(t.TempDir(), "mytool.bash")
+	if err := os.WriteFile(script, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		words string
+		want  string
+	}{
+		{words: `mytool ""`, want: "server srv"},
+		{words: `mytool s`, want: "server srv"},
+		{words: `mytool srv ""`, want: "run"},
+		{words: `mytool -l server ""`, want: "server srv"},
+		{words: `mytool server run --p`, want: "--port"},
+		{words: `mytool server run -p 80 --n`, want: "--no-cache"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.words, func(t *testing.T) {
+			cmd := exec.Command(bash, "--norc", "-c", `source "$1"; COMP_WORDS=(`+tt.words+`); COMP_CWORD=$((${#COMP_WORDS[@]} - 1)); _mytool; echo -n "${COMPREPLY[*]}"`, "bash", script)
+			out, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Fatalf("bash: %v\n%s", err, out)
+			}
+			if string(out) != tt.want {
+				t.Errorf("got %q, want %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompletionCommand(t *testing.T) {
+	var got, want bytes.Buffer
+	root := newCompletionTree()
+	if err := root.ExecuteArgs(context.Background(), []string{"completion", "fish"}, gommand.WithStdout(&got), gommand.WithCompletionCommand()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := root.GenCompletion(&want, "fish"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(got.String(), "-a 'completion'") {
+		t.Errorf("expected completion command to complete itself:\n%s", got.String())
+	}
+	// the built-in commands are not left registered on the tree
+	if strings.Contains(want.String(), "-a 'completion'") {
+		t.Errorf("expected completion command to be unregistered after execution:\n%s", want.String())
+	}
+
+	t.Run("user defined completion command", func(t *testing.T) {
+		var ran bool
+		root := newCompletionTree()
+		root.SubCommand(&gommand.Command{Name: "completion", Run: func(*gommand.Context) error { ran = true; return nil }})
+		if err := root.ExecuteArgs(context.Background(), []string{"completion"}, gommand.WithCompletionCommand()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ran {
+			t.Error("expected user defined completion command to run")
+		}
+		var out bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"__complete", "--", "s"}, gommand.WithStdout(&out), gommand.WithCompletionCommand()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(out.String(), "server\tmanage servers\n") {
+			t.Errorf("expected __complete to be registered, got:\n%s", out.String())
+		}
+	})
+}
+
+func TestCompleteSkipsHooks(t *testing.T) {
+	var ran []string
+	root := &gommand.Command{
+		Name:              "mytool",
+		PersistentFlagSet: flags.NewFlagSet().AddFlags(flags.StringFlag("token", "", "api token").Required()),
+		PersistentPreRun:  func(*gommand.Context) error { ran = append(ran, "pre"); return nil },
+		PersistentPostRun: func(*gommand.Context) error { ran = append(ran, "post"); return nil },
+	}
+	root.SubCommand(&gommand.Command{Name: "server", Usage: "manage servers"})
+
+	var buf bytes.Buffer
+	if err := root.ExecuteArgs(context.Background(), []string{"__complete", "--", "s"}, gommand.WithStdout(&buf), gommand.WithCompletionCommand()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got, want := buf.String(), "server\tmanage servers\n:2\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if len(ran) > 0 {
+		t.Errorf("expected no hooks to run, got %v", ran)
+	}
+}
+
+func TestDynamicCompletion(t *testing.T) {
+	tests := []struct {
+		name  string
+		words []string
+		want  string
+	}{
+		{name: "subcommands", words: []string{"s"}, want: "server\tmanage servers\nsrv\tmanage servers\n:2\n"},
+		{name: "flags", words: []string{"server", "--c"}, want: "--cluster\tcluster to use\n--config\tconfig file\n:2\n"},
+		{name: "flag value", words: []string{"--cluster", ""}, want: "prod\tproduction\nstaging\n:2\n"},
+		{name: "short flag value", words: []string{"-c", "p"}, want: "prod\tproduction\nstaging\n:2\n"},
+		{name: "clustered flag value", words: []string{"-vc", ""}, want: "prod\tproduction\nstaging\n:2\n"},
+		{name: "flag value with equals", words: []string{"--cluster=p"}, want: "--cluster=prod\tproduction\n--cluster=staging\n:2\n"},
+		{name: "file extension directive", words: []string{"--config", ""}, want: "yaml\nyml\n:4\n"},
+		{name: "flag without completion", words: []string{"--output", ""}, want: ":0\n"},
+		{name: "args with parsed flags", words: []string{"-c", "prod", "srv", "get", "a", ""}, want: "prod-1\n:2\n"},
+		{name: "after terminator", words: []string{"server", "get", "--", "--c"}, want: "-0\n:2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &gommand.Command{
+				Name: "mytool",
+				PersistentFlagSet: flags.NewFlagSet().AddFlags(
+					flags.Completion(flags.StringFlagS("cluster", 'c', "", "cluster to use"), func(ctx flags.CompletionContext, toComplete string) ([]string, flags.CompletionDirective) {
+						return []string{"prod\tproduction", "staging"}, flags.CompleteNoFile
+					}),
+					flags.Completion(flags.StringFlag("config", "", "config file"), func(flags.CompletionContext, string) ([]string, flags.CompletionDirective) {
+						return []string{"yaml", "yml"}, flags.CompleteFilterExt
+					}),
+					flags.BoolFlagS("verbose", 'v', false, "verbose output"),
+					flags.StringFlag("output", "", "output file"),
+				),
+			}
+			server := &gommand.Command{Name: "server", Usage: "manage servers", Aliases: []string{"srv"}}
+			server.SubCommand(&gommand.Command{
+				Name:         "get",
+				Usage:        "get a server",
+				ArgValidator: gommand.ArgsAny(),
+				Complete: func(ctx *gommand.Context, toComplete string) ([]string, flags.CompletionDirective) {
+					cluster := ctx.Flags().String("cluster")
+					return []string{fmt.Sprintf("%s-%d", cluster, len(ctx.Args()))}, flags.CompleteNoFile
+				},
+				Run: func(*gommand.Context) error { return nil },
+			})
+			root.SubCommand(server, &gommand.Command{Name: "debug", Hidden: true})
+
+			var buf bytes.Buffer
+			args := append([]string{"__complete", "--"}, tt.words...)
+			if err := root.ExecuteArgs(context.Background(), args, gommand.WithStdout(&buf), gommand.WithCompletionCommand()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package gommand
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"os"
 
@@ -9,17 +10,30 @@ import (
 	"github.com/jimmykodes/gommand/internal/lexer"
 )
 
+var _ flags.CompletionContext = (*Context)(nil)
+
 type Context struct {
 	context.Context
-	cmd          *Command
-	args         []string
-	preRuns      []func(*Context) error
-	postRuns     []func(*Context) error
-	deferPost    bool
-	silenceHelp  bool
-	silenceError bool
-	depth        int
-	lexer        *lexer.Lexer
+	cmd            *Command
+	args           []string
+	argPositions   []int
+	passthrough    []string
+	preRuns        []func(*Context) error
+	postRuns       []func(*Context) error
+	deferPost      bool
+	silenceHelp    bool
+	silenceError   bool
+	prefixMatching bool
+	enforceUsage   bool
+	depth          int
+	lexer          *lexer.Lexer
+
+	argv              []string
+	origins           []int
+	responseFileDepth int
+	helpCommand       bool
+	completionCommand bool
+	prompt            string
 
 	stdin  io.Reader
 	stdout io.Writer
@@ -28,10 +42,79 @@ type Context struct {
 	persistentFlagSets []*flags.FlagSet
 
 	flagGetter *flags.FlagGetter
+	namedArgs  *flags.FlagGetter
+
+	// warned records the deprecation warnings already printed
+	warned map[string]bool
+
+	// flagPositions are the positions of the flags read, the last one if a flag is repeated
+	flagPositions map[flags.Flag]int
+
+	// userAliases are the user aliases read when the execution began, see Command.expandAliases
+	userAliases map[string]string
+
+	// pluginNames caches the plugins found by scanPlugins, as scanning for them reads
+	// every directory on $PATH
+	pluginNames []string
+	scanned     bool
 
 	err error
 }
 
+// warnDeprecated prints a warning to Stderr that what is deprecated, at most once per execution
+func (c *Context) warnDeprecated(what, msg string) {
+	if c.warned[what] {
+		return
+	}
+	if c.warned == nil {
+		c.warned = make(map[string]bool)
+	}
+	c.warned[what] = true
+	_, _ = fmt.Fprintf(c.Stderr(), "Warning: %s is deprecated: %s\n", what, msg)
+}
+
+// flagRead records the most recently read argument as the position of f. A count flag is
+// reset the first time it is read, so it counts from its default in every execution rather
+// than adding to the count of the last one.
+func (c *Context) flagRead(f flags.Flag) {
+	if c.flagPositions == nil {
+		c.flagPositions = make(map[flags.Flag]int)
+	}
+	if _, read := c.flagPositions[f]; !read && f.Type() == flags.CountFlagType {
+		flags.Reset(f)
+	}
+	c.flagPositions[f] = c.lastPosition()
+}
+
+// position returns the index in argv of the argument at index i of the args being parsed,
+// which differ from argv once user aliases and response files are expanded. Arguments from
+// an expansion are located at the alias or response file they replaced.
+func (c *Context) position(i int) int {
+	switch {
+	case i < 0 || c.origins == nil:
+		return i
+	case i >= len(c.origins):
+		// past the last argument, ie: where a missing one is expected
+		return len(c.argv) + i - len(c.origins)
+	default:
+		return c.origins[i]
+	}
+}
+
+// lastPosition returns the position in argv of the most recently read argument, see position
+func (c *Context) lastPosition() int {
+	pos, _ := c.lexer.Last()
+	return c.position(pos)
+}
+
+// scanPlugins returns the plugins of cmd, scanning for them at most once per execution
+func (c *Context) scanPlugins(cmd *Command) []string {
+	if !c.scanned {
+		c.pluginNames, c.scanned = cmd.plugins(), true
+	}
+	return c.pluginNames
+}
+
 func (c *Context) addPersistentFlags(fs *flags.FlagSet) {
 	c.persistentFlagSets = append(c.persistentFlagSets, fs)
 }
@@ -48,6 +131,13 @@ func (c *Context) Args() []string {
 	return c.args
 }
 
+// PassthroughArgs returns the arguments that appeared after the `--` terminator.
+// These are never parsed as flags or subcommands and are not passed to the
+// command's ArgValidator.
+func (c *Context) PassthroughArgs() []string {
+	return c.passthrough
+}
+
 // Arg will return the command line argument at the given index
 // Returns an empty string if idx is out of range
 func (c *Context) Arg(idx int) string {
@@ -57,6 +147,15 @@ func (c *Context) Arg(idx int) string {
 	return ""
 }
 
+// NamedArgs returns the values of the named positional args declared in Command.Args,
+// using the same getters as Flags. ie: ctx.NamedArgs().Int("replicas")
+func (c *Context) NamedArgs() *flags.FlagGetter {
+	if c.namedArgs == nil {
+		return flags.NewFlagGetter(flags.NewFlagSet())
+	}
+	return c.namedArgs
+}
+
 func (c *Context) Flags() *flags.FlagGetter {
 	return c.flagGetter
 }
@@ -2,6 +2,7 @@ package gommand_test
 
 import (
 	"errors"
+	"slices"
 	"testing"
 
 	"github.com/jimmykodes/gommand"
@@ -81,6 +82,93 @@ func TestContextArgs(t *testing.T) {
 	})
 }
 
+func TestContextPassthroughArgs(t *testing.T) {
+	t.Run("args after -- are passed through untouched", func(t *testing.T) {
+		defer overwriteArgs([]string{"exec", "--", "kubectl", "get", "pods", "-o", "yaml"})()
+
+		var args, passthrough []string
+		root := &gommand.Command{Name: "root"}
+		root.SubCommand(&gommand.Command{
+			Name:         "exec",
+			ArgValidator: gommand.ArgsNone(),
+			Run: func(ctx *gommand.Context) error {
+				args = ctx.Args()
+				passthrough = ctx.PassthroughArgs()
+				return nil
+			},
+		})
+
+		if err := root.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(args) != 0 {
+			t.Errorf("Args() = %v, want none", args)
+		}
+		want := []string{"kubectl", "get", "pods", "-o", "yaml"}
+		if !slices.Equal(passthrough, want) {
+			t.Errorf("PassthroughArgs() = %v, want %v", passthrough, want)
+		}
+	})
+
+	t.Run("args before -- are validated independently", func(t *testing.T) {
+		defer overwriteArgs([]string{"a", "--verbose", "--", "sub", "--", "-x"})()
+
+		fs := flags.NewFlagSet()
+		fs.Bool("verbose", false, "")
+
+		var (
+			args, passthrough []string
+			verbose           bool
+		)
+		root := &gommand.Command{
+			Name:         "root",
+			FlagSet:      fs,
+			ArgValidator: gommand.ArgsExact(1),
+			Run: func(ctx *gommand.Context) error {
+				args = ctx.Args()
+				passthrough = ctx.PassthroughArgs()
+				verbose = ctx.Flags().Bool("verbose")
+				return nil
+			},
+		}
+		root.SubCommand(&gommand.Command{
+			Name: "sub",
+			Run:  func(ctx *gommand.Context) error { return errors.New("sub should not run") },
+		})
+
+		if err := root.Execute(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(args, []string{"a"}) {
+			t.Errorf("Args() = %v, want [a]", args)
+		}
+		if !slices.Equal(passthrough, []string{"sub", "--", "-x"}) {
+			t.Errorf("PassthroughArgs() = %v, want [sub -- -x]", passthrough)
+		}
+		if !verbose {
+			t.Error("expected --verbose before -- to be parsed")
+		}
+	})
+
+	t.Run("flag before -- does not consume the terminator as its value", func(t *testing.T) {
+		defer overwriteArgs([]string{"--name", "--", "value"})()
+
+		fs := flags.NewFlagSet()
+		fs.String("name", "default", "")
+
+		cmd := &gommand.Command{
+			Name:    "cmd",
+			FlagSet: fs,
+			Run:     func(ctx *gommand.Context) error { return errors.New("cmd should not run") },
+		}
+
+		err := cmd.Execute()
+		if err == nil || err.Error() != "gommand: missing value for flag --name" {
+			t.Errorf("got error %v, want missing value for flag --name", err)
+		}
+	})
+}
+
 func TestContextFlags(t *testing.T) {
 	// ctx.Flags() exposes local flags registered on the command.
 	t.Run("ctx.Flags() exposes local flags", func(t *testing.T) {
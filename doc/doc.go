@@ -0,0 +1,97 @@
+// Package doc generates documentation for a gommand.Command tree.
+//
+// Man pages and Markdown pages are generated from the same data as the help text,
+// so hidden and deprecated commands and flags are left out.
+//
+//	if err := doc.GenManTree(root, &doc.ManHeader{Section: "1"}, "./man"); err != nil {
+//		log.Fatal(err)
+//	}
+//	if err := doc.GenMarkdownTree(root, "./docs"); err != nil {
+//		log.Fatal(err)
+//	}
+package doc
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
+)
+
+// basename returns the name, without extension, of the page generated for cmd
+// ie: mytool-server-run
+func basename(cmd *gommand.Command) string {
+	return strings.ReplaceAll(cmd.HelpData().Path, " ", "-")
+}
+
+// walk calls fn for cmd and each of its subcommands that is not hidden, depth first
+func walk(cmd *gommand.Command, fn func(*gommand.Command) error) error {
+	if err := fn(cmd); err != nil {
+		return err
+	}
+	for _, sub := range cmd.HelpData().Commands {
+		if err := walk(sub.Command, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// genTree writes a page for cmd and each of its subcommands to dir, using gen to render them
+func genTree(cmd *gommand.Command, dir, ext string, gen func(*gommand.Command, io.Writer) error) error {
+	return walk(cmd, func(cmd *gommand.Command) error {
+		f, err := os.Create(filepath.Join(dir, basename(cmd)+ext))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if err := gen(cmd, f); err != nil {
+			return err
+		}
+		return f.Close()
+	})
+}
+
+// envVar returns the environment variable the flag can be set from, or an
+// empty string if flags.Environ is not one of its sources
+func envVar(f flags.Flag) string {
+	for _, source := range f.Sources() {
+		if source == flags.Environ {
+			return flags.EnvName(f.Name())
+		}
+	}
+	return ""
+}
+
+// flagNames returns the names of the flag as they are passed on the command line
+// ie: -p, --port
+func flagNames(f flags.Flag) string {
+	name := "--" + flags.DisplayName(f)
+	if f.Short() != 0 {
+		name = "-" + string(f.Short()) + ", " + name
+	}
+	return name
+}
+
+// flagUsage returns the usage of the flag, followed by any annotations the help text includes
+func flagUsage(f flags.Flag) string {
+	usage := f.Usage()
+	if f.Type() == flags.CountFlagType {
+		usage += " (count)"
+	}
+	if f.IsRequired() {
+		usage += " (required)"
+	}
+	return usage
+}
+
+// description returns the Description of the command, falling back to its Usage
+func description(data gommand.HelpData) string {
+	if data.Description != "" {
+		return data.Description
+	}
+	return data.Usage
+}
@@ -0,0 +1,30 @@
+package doc_test
+
+import (
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
+)
+
+func newTree() (root, server, run *gommand.Command) {
+	noop := func(*gommand.Context) error { return nil }
+	root = &gommand.Command{
+		Name:  "mytool",
+		Usage: "my tool",
+		PersistentFlagSet: flags.NewFlagSet().AddFlags(
+			flags.StringFlag("log-level", "info", "log level").AddSources(flags.Environ),
+			flags.BoolFlag("trace", false, "trace calls").Hidden(),
+		),
+	}
+	server = &gommand.Command{Name: "server", Usage: "manage servers", Aliases: []string{"srv"}}
+	run = &gommand.Command{
+		Name:        "run [--port port]",
+		Usage:       "run a server",
+		Description: "Run a server.\n.Listens on the given port.",
+		FlagSet:     flags.NewFlagSet().AddFlags(flags.IntFlagS("port", 'p', 8080, "port | number").Required()),
+		Args:        []gommand.Arg{{Flag: flags.StringSliceFlag("names", nil, "names of the servers"), Optional: true, Variadic: true}},
+		Run:         noop,
+	}
+	server.SubCommand(run)
+	root.SubCommand(server, &gommand.Command{Name: "secret", Hidden: true, Run: noop})
+	return root, server, run
+}
@@ -0,0 +1,166 @@
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
+)
+
+// ManHeader is the header of generated man pages
+type ManHeader struct {
+	// Section is the manual section the pages belong to. Defaults to 1
+	Section string
+
+	// Date is the date the pages were last updated. Defaults to the current date
+	Date time.Time
+
+	// Source is the source of the command. ie: mytool 1.2.3
+	Source string
+
+	// Manual is the title of the manual. ie: MyTool Manual
+	Manual string
+}
+
+func (h *ManHeader) withDefaults() ManHeader {
+	var out ManHeader
+	if h != nil {
+		out = *h
+	}
+	if out.Section == "" {
+		out.Section = "1"
+	}
+	if out.Date.IsZero() {
+		out.Date = time.Now()
+	}
+	return out
+}
+
+// GenManTree writes a man page for cmd and each of its subcommands that is not hidden
+// to dir, named after the command's path. ie: mytool-server-run.1
+func GenManTree(cmd *gommand.Command, header *ManHeader, dir string) error {
+	h := header.withDefaults()
+	return genTree(cmd, dir, "."+h.Section, func(cmd *gommand.Command, w io.Writer) error {
+		return GenMan(cmd, &h, w)
+	})
+}
+
+// GenMan writes the man page for cmd to w
+func GenMan(cmd *gommand.Command, header *ManHeader, w io.Writer) error {
+	var (
+		h    = header.withDefaults()
+		data = cmd.HelpData()
+		name = basename(cmd)
+		buf  bytes.Buffer
+	)
+
+	_, _ = fmt.Fprintf(&buf, ".TH %s %s %s %s %s\n",
+		roffQuote(strings.ToUpper(name)), roffQuote(h.Section), roffQuote(h.Date.Format("Jan 2006")),
+		roffQuote(h.Source), roffQuote(h.Manual),
+	)
+	buf.WriteString(".nh\n.ad l\n")
+
+	buf.WriteString(".SH NAME\n")
+	buf.WriteString(roffEscape(name))
+	if data.Usage != "" {
+		buf.WriteString(` \- ` + roffEscape(data.Usage))
+	}
+	buf.WriteString("\n")
+
+	buf.WriteString(".SH SYNOPSIS\n")
+	buf.WriteString(`\fB` + roffEscape(data.UsageLine) + `\fR`)
+	if len(data.Commands) > 0 {
+		buf.WriteString(" [commands]")
+	}
+	buf.WriteString("\n")
+
+	if desc := description(data); desc != "" {
+		buf.WriteString(".SH DESCRIPTION\n")
+		buf.WriteString(roffEscape(desc) + "\n")
+	}
+
+	if len(data.Aliases) > 0 {
+		buf.WriteString(".SH ALIASES\n")
+		buf.WriteString(roffEscape(strings.Join(data.Aliases, ", ")) + "\n")
+	}
+
+	if len(data.Args) > 0 {
+		buf.WriteString(".SH ARGUMENTS\n")
+		for _, arg := range data.Args {
+			_, _ = fmt.Fprintf(&buf, ".TP\n\\fI%s\\fR\n%s\n", roffEscape(arg.Synopsis()), roffEscape(arg.Flag.Usage()))
+		}
+	}
+
+	writeManFlags(&buf, "OPTIONS", data.Flags)
+	writeManFlags(&buf, "GLOBAL OPTIONS", data.InheritedFlags)
+
+	var env []flags.Flag
+	for _, f := range slices.Concat(data.Flags, data.InheritedFlags) {
+		if envVar(f) != "" {
+			env = append(env, f)
+		}
+	}
+	if len(env) > 0 {
+		buf.WriteString(".SH ENVIRONMENT\n")
+		for _, f := range env {
+			_, _ = fmt.Fprintf(&buf, ".TP\n\\fB%s\\fR\nSets \\fB%s\\fR\n", roffEscape(envVar(f)), roffEscape("--"+f.Name()))
+		}
+	}
+
+	var seeAlso []string
+	if parent := cmd.Parent(); parent != nil {
+		seeAlso = append(seeAlso, manRef(basename(parent), h.Section))
+	}
+	for _, sub := range data.Commands {
+		seeAlso = append(seeAlso, manRef(basename(sub.Command), h.Section))
+	}
+	if len(seeAlso) > 0 {
+		buf.WriteString(".SH SEE ALSO\n")
+		buf.WriteString(strings.Join(seeAlso, ", ") + "\n")
+	}
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func writeManFlags(buf *bytes.Buffer, title string, fs []flags.Flag) {
+	if len(fs) == 0 {
+		return
+	}
+	buf.WriteString(".SH " + title + "\n")
+	for _, f := range fs {
+		names := strings.Split(flagNames(f), ", ")
+		for i, name := range names {
+			names[i] = `\fB` + roffEscape(name) + `\fR`
+		}
+		_, _ = fmt.Fprintf(buf, ".TP\n%s\n%s\n", strings.Join(names, ", "), roffEscape(flagUsage(f)))
+	}
+}
+
+// manRef returns a reference to another man page. ie: \fBmytool-server\fR(1)
+func manRef(name, section string) string {
+	return `\fB` + roffEscape(name) + `\fR(` + section + ")"
+}
+
+// roffQuote returns s as a quoted roff macro argument
+func roffQuote(s string) string {
+	return `"` + strings.ReplaceAll(roffEscape(s), `"`, `""`) + `"`
+}
+
+// roffEscape escapes s so it is rendered literally in a man page
+func roffEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		// lines starting with a control character would be interpreted as requests
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
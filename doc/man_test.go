@@ -0,0 +1,85 @@
+package doc_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jimmykodes/gommand/doc"
+)
+
+func TestGenMan(t *testing.T) {
+	_, _, run := newTree()
+	header := &doc.ManHeader{Date: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), Source: "mytool 1.0.0"}
+
+	var buf bytes.Buffer
+	if err := doc.GenMan(run, header, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `.TH "MYTOOL\-SERVER\-RUN" "1" "Jan 2026" "mytool 1.0.0" ""
+.nh
+.ad l
+.SH NAME
+mytool\-server\-run \- run a server
+.SH SYNOPSIS
+\fBmytool server run [\-\-port port]\fR
+.SH DESCRIPTION
+Run a server.
+\&.Listens on the given port.
+.SH ARGUMENTS
+.TP
+\fI[names...]\fR
+names of the servers
+.SH OPTIONS
+.TP
+\fB\-h\fR, \fB\-\-help\fR
+show this help message
+.TP
+\fB\-p\fR, \fB\-\-port\fR
+port | number (required)
+.SH GLOBAL OPTIONS
+.TP
+\fB\-\-log\-level\fR
+log level
+.SH ENVIRONMENT
+.TP
+\fBLOG_LEVEL\fR
+Sets \fB\-\-log\-level\fR
+.SH SEE ALSO
+\fBmytool\-server\fR(1)
+`
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenManTree(t *testing.T) {
+	root, _, _ := newTree()
+	dir := t.TempDir()
+	if err := doc.GenManTree(root, &doc.ManHeader{Section: "8"}, dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	want := []string{"mytool-server-run.8", "mytool-server.8", "mytool.8"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got files %v, want %v", got, want)
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "mytool-server.8"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(page, []byte(".SH SEE ALSO\n\\fBmytool\\fR(8), \\fBmytool\\-server\\-run\\fR(8)\n")) {
+		t.Errorf("missing SEE ALSO links:\n%s", page)
+	}
+}
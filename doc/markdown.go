@@ -0,0 +1,130 @@
+package doc
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
+)
+
+// GenMarkdownTree writes a Markdown page for cmd and each of its subcommands that is not hidden
+// to dir, named after the command's path, ie: mytool-server-run.md, along with an index.md
+// linking to all of them.
+func GenMarkdownTree(cmd *gommand.Command, dir string) error {
+	if err := genTree(cmd, dir, ".md", GenMarkdown); err != nil {
+		return err
+	}
+	f, err := os.Create(filepath.Join(dir, "index.md"))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := GenMarkdownIndex(cmd, f); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// GenMarkdown writes the Markdown page for cmd to w
+func GenMarkdown(cmd *gommand.Command, w io.Writer) error {
+	var (
+		data = cmd.HelpData()
+		buf  bytes.Buffer
+	)
+
+	buf.WriteString("# " + data.Path + "\n\n")
+	if desc := description(data); desc != "" {
+		buf.WriteString(desc + "\n\n")
+	}
+
+	buf.WriteString("## Usage\n\n```\n" + data.UsageLine)
+	if len(data.Commands) > 0 {
+		buf.WriteString(" [commands]")
+	}
+	buf.WriteString("\n```\n\n")
+
+	if len(data.Aliases) > 0 {
+		buf.WriteString("## Aliases\n\n")
+		buf.WriteString(strings.Join(data.Aliases, ", ") + "\n\n")
+	}
+
+	if len(data.Args) > 0 {
+		buf.WriteString("## Arguments\n\n")
+		buf.WriteString("| Argument | Description |\n| --- | --- |\n")
+		for _, arg := range data.Args {
+			_, _ = fmt.Fprintf(&buf, "| `%s` | %s |\n", arg.Synopsis(), tableCell(arg.Flag.Usage()))
+		}
+		buf.WriteString("\n")
+	}
+
+	if len(data.Commands) > 0 {
+		buf.WriteString("## Commands\n\n")
+		buf.WriteString("| Command | Description |\n| --- | --- |\n")
+		for _, sub := range data.Commands {
+			_, _ = fmt.Fprintf(&buf, "| [%s](%s.md) | %s |\n", sub.Name, basename(sub.Command), tableCell(sub.Usage))
+		}
+		buf.WriteString("\n")
+	}
+
+	writeMarkdownFlags(&buf, "Flags", data.Flags)
+	writeMarkdownFlags(&buf, "Global Flags", data.InheritedFlags)
+
+	if parent := cmd.Parent(); parent != nil {
+		buf.WriteString("## See Also\n\n")
+		_, _ = fmt.Fprintf(&buf, "- [%s](%s.md)\n\n", parent.HelpData().Path, basename(parent))
+	}
+
+	_, err := w.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+	return err
+}
+
+// GenMarkdownIndex writes a Markdown page to w linking to the pages of cmd and
+// each of its subcommands that is not hidden
+func GenMarkdownIndex(cmd *gommand.Command, w io.Writer) error {
+	var (
+		buf   bytes.Buffer
+		depth = strings.Count(cmd.HelpData().Path, " ")
+	)
+	buf.WriteString("# " + cmd.HelpData().Path + "\n\n")
+	err := walk(cmd, func(cmd *gommand.Command) error {
+		data := cmd.HelpData()
+		indent := strings.Repeat("  ", strings.Count(data.Path, " ")-depth)
+		_, _ = fmt.Fprintf(&buf, "%s- [%s](%s.md)", indent, data.Path, basename(cmd))
+		if data.Usage != "" {
+			buf.WriteString(": " + data.Usage)
+		}
+		buf.WriteString("\n")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+func writeMarkdownFlags(buf *bytes.Buffer, title string, fs []flags.Flag) {
+	if len(fs) == 0 {
+		return
+	}
+	buf.WriteString("## " + title + "\n\n")
+	buf.WriteString("| Flag | Environment | Description |\n| --- | --- | --- |\n")
+	for _, f := range fs {
+		env := envVar(f)
+		if env != "" {
+			env = "`" + env + "`"
+		}
+		_, _ = fmt.Fprintf(buf, "| `%s` | %s | %s |\n", flagNames(f), env, tableCell(flagUsage(f)))
+	}
+	buf.WriteString("\n")
+}
+
+// tableCell escapes s to be used in a Markdown table cell
+func tableCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,131 @@
+package doc_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/doc"
+)
+
+func TestGenMarkdown(t *testing.T) {
+	_, server, _ := newTree()
+
+	var buf bytes.Buffer
+	if err := doc.GenMarkdown(server, &buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "# mytool server\n" +
+		"\n" +
+		"manage servers\n" +
+		"\n" +
+		"## Usage\n" +
+		"\n" +
+		"```\n" +
+		"mytool server [commands]\n" +
+		"```\n" +
+		"\n" +
+		"## Aliases\n" +
+		"\n" +
+		"srv\n" +
+		"\n" +
+		"## Commands\n" +
+		"\n" +
+		"| Command | Description |\n" +
+		"| --- | --- |\n" +
+		"| [run](mytool-server-run.md) | run a server |\n" +
+		"\n" +
+		"## Flags\n" +
+		"\n" +
+		"| Flag | Environment | Description |\n" +
+		"| --- | --- | --- |\n" +
+		"| `-h, --help` |  | show this help message |\n" +
+		"\n" +
+		"## Global Flags\n" +
+		"\n" +
+		"| Flag | Environment | Description |\n" +
+		"| --- | --- | --- |\n" +
+		"| `--log-level` | `LOG_LEVEL` | log level |\n" +
+		"\n" +
+		"## See Also\n" +
+		"\n" +
+		"- [mytool](mytool.md)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGenMarkdownTree(t *testing.T) {
+	root, _, _ := newTree()
+	dir := t.TempDir()
+	if err := doc.GenMarkdownTree(root, dir); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	want := []string{"index.md", "mytool-server-run.md", "mytool-server.md", "mytool.md"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got files %v, want %v", got, want)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantIndex := "# mytool\n" +
+		"\n" +
+		"- [mytool](mytool.md): my tool\n" +
+		"  - [mytool server](mytool-server.md): manage servers\n" +
+		"    - [mytool server run](mytool-server-run.md): run a server\n"
+	if string(index) != wantIndex {
+		t.Errorf("got index:\n%s\nwant:\n%s", index, wantIndex)
+	}
+
+	run, err := os.ReadFile(filepath.Join(dir, "mytool-server-run.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(run, []byte("| `-p, --port` |  | port \\| number (required) |\n")) {
+		t.Errorf("missing escaped flag row:\n%s", run)
+	}
+	if !bytes.Contains(run, []byte("## Arguments\n\n| Argument | Description |\n| --- | --- |\n| `[names...]` | names of the servers |\n")) {
+		t.Errorf("missing arguments table:\n%s", run)
+	}
+}
+
+func TestGenMarkdownMachineIndependent(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	root, _, _ := newTree()
+	var want bytes.Buffer
+	if err := doc.GenMarkdown(root, &want); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pathDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(pathDir, "mytool-hello"), []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", pathDir)
+	root.Plugins = true
+	root.UserAliases = gommand.AliasMap{"dp": "server run --port 80"}
+
+	var got bytes.Buffer
+	if err := doc.GenMarkdown(root, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.String() != want.String() {
+		t.Errorf("expected plugins and user aliases to be left out, got:\n%s\nwant:\n%s", &got, &want)
+	}
+}
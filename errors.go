@@ -0,0 +1,218 @@
+package gommand
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jimmykodes/gommand/flags"
+	"github.com/jimmykodes/gommand/internal/suggest"
+)
+
+var (
+	ErrNoRunner    = errors.New("gommand: command has no run function")
+	errShowHelp    = errors.New("show help")
+	errShowVersion = errors.New("show version")
+
+	errMultiFlagValue   = errors.New("cannot assign value to boolean or count flags in a multi-flag")
+	errMissingFlagValue = errors.New("missing value")
+)
+
+// Location identifies where on the command line a parse error occurred
+type Location struct {
+	// CommandPath is the path of the command being parsed when the error occurred.
+	// ie: mytool server run
+	CommandPath string
+
+	// Position is the index of the offending argument, not including the program name,
+	// or -1 if the error was not caused by a single argument. It indexes the args as they
+	// were passed, before user aliases and response files are expanded; an argument from an
+	// expansion is located at the alias or @file argument it replaced.
+	Position int
+}
+
+// ErrUnknownFlag is returned when a flag is passed that is not defined on the command
+// or any of its parents' persistent flags.
+type ErrUnknownFlag struct {
+	Location
+	// Flag is the flag as it was passed, including leading dashes. ie: --prot
+	Flag string
+	// Suggestions are the defined flags that are likely what was meant
+	Suggestions []string
+}
+
+func (e ErrUnknownFlag) Error() string {
+	return "gommand: unknown flag: " + e.Flag + didYouMean(e.Suggestions)
+}
+
+// ErrUnknownCommand is returned when the first argument to a command with subcommands
+// looks like a mistyped subcommand and the command could not otherwise handle it.
+type ErrUnknownCommand struct {
+	Location
+	Name string
+	// Suggestions are the names and aliases of subcommands that are likely what was meant
+	Suggestions []string
+}
+
+func (e ErrUnknownCommand) Error() string {
+	return "gommand: unknown command: " + e.Name + didYouMean(e.Suggestions)
+}
+
+// ErrAmbiguousCommand is returned when Command.PrefixMatching is enabled and a subcommand
+// prefix matches more than one subcommand.
+type ErrAmbiguousCommand struct {
+	Location
+	Prefix  string
+	Matches []string
+}
+
+func (e ErrAmbiguousCommand) Error() string {
+	return "gommand: ambiguous command: " + e.Prefix + didYouMean(e.Matches)
+}
+
+// ErrAmbiguousFlag is returned when Command.PrefixMatching is enabled and a long flag
+// prefix matches more than one flag. It wraps the flags.ErrAmbiguousFlag returned by
+// FlagSet.FromPrefix.
+type ErrAmbiguousFlag struct {
+	Location
+	// Prefix is the flag as it was passed, without leading dashes. ie: ver
+	Prefix  string
+	Matches []string
+}
+
+func (e ErrAmbiguousFlag) Error() string {
+	return e.Unwrap().Error()
+}
+
+func (e ErrAmbiguousFlag) Unwrap() error {
+	return flags.ErrAmbiguousFlag{Prefix: e.Prefix, Matches: e.Matches}
+}
+
+// ErrInvalidFlagValue is returned when a value passed to a flag cannot be set on it
+type ErrInvalidFlagValue struct {
+	Location
+	Flag flags.Flag
+	// Token is the argument containing the offending value
+	Token string
+	Value string
+	Err   error
+}
+
+func (e ErrInvalidFlagValue) Error() string {
+	if errors.Is(e.Err, errMissingFlagValue) {
+		return "gommand: missing value for flag --" + e.Flag.Name()
+	}
+	return fmt.Sprintf("gommand: invalid value %q for flag --%s: %v", e.Value, e.Flag.Name(), e.Err)
+}
+
+func (e ErrInvalidFlagValue) Unwrap() error {
+	return e.Err
+}
+
+// ErrInvalidArgs is returned when the command's ArgValidator rejects its args
+type ErrInvalidArgs struct {
+	Location
+	Args []string
+	Err  error
+}
+
+func (e ErrInvalidArgs) Error() string {
+	return "gommand: invalid args: " + e.Err.Error()
+}
+
+func (e ErrInvalidArgs) Unwrap() error {
+	return e.Err
+}
+
+// ErrArg is returned by ArgValidators rejecting a single arg, and is wrapped by ErrInvalidArgs
+type ErrArg struct {
+	// Index is the index of the arg, as passed to Context.Arg
+	Index int
+	Value string
+	Err   error
+}
+
+func (e ErrArg) Error() string {
+	return fmt.Sprintf("argument %d %q: %v", e.Index, e.Value, e.Err)
+}
+
+func (e ErrArg) Unwrap() error {
+	return e.Err
+}
+
+// ErrUsage is returned when Command.EnforceUsage is set and the command line does not match
+// the usage grammar in the command's Name. Its Position is that of the offending argument, or,
+// if a required part of the usage is missing, the end of the command line, where it belongs.
+type ErrUsage struct {
+	Location
+	// Usage is the part of the usage that is not matched. ie: {--from-file file | --from-gcs bucket}
+	Usage string
+	Msg   string
+}
+
+func (e ErrUsage) Error() string {
+	return fmt.Sprintf("gommand: %s: %s in usage %q", e.CommandPath, e.Msg, e.Usage)
+}
+
+// ErrMisorderedArgs is returned when args are passed to a command before one of its subcommands
+type ErrMisorderedArgs struct {
+	Location
+	Args    []string
+	Command string
+}
+
+func (e ErrMisorderedArgs) Error() string {
+	return fmt.Sprintf("gommand: invalid arg ordering. arguments %v appear before subcommand %s", e.Args, e.Command)
+}
+
+// ErrConflict is returned by Command.Validate for every conflict found in the command tree
+type ErrConflict struct {
+	// CommandPath is the path of the command on which the conflict was found
+	CommandPath string
+	Msg         string
+}
+
+func (e ErrConflict) Error() string {
+	return "gommand: " + e.CommandPath + ": " + e.Msg
+}
+
+// ErrUnsupportedShell is returned when generating a completion script for a shell that is not one of Shells
+type ErrUnsupportedShell struct {
+	Shell string
+}
+
+func (e ErrUnsupportedShell) Error() string {
+	return fmt.Sprintf("gommand: unsupported shell %q: expected one of %s", e.Shell, suggest.JoinOr(Shells))
+}
+
+// ErrPluginExit is returned when a plugin, see Command.Plugins, exits with a non-zero exit code.
+// The plugin is expected to have reported the failure itself, so it is not printed.
+//
+// ex:
+//
+//	if err := root.Execute(); err != nil {
+//		var exitErr gommand.ErrPluginExit
+//		if errors.As(err, &exitErr) {
+//			os.Exit(exitErr.Code)
+//		}
+//		os.Exit(1)
+//	}
+type ErrPluginExit struct {
+	Plugin string
+	Code   int
+	Err    error
+}
+
+func (e ErrPluginExit) Error() string {
+	return fmt.Sprintf("gommand: plugin %s exited with code %d", e.Plugin, e.Code)
+}
+
+func (e ErrPluginExit) Unwrap() error {
+	return e.Err
+}
+
+func didYouMean(suggestions []string) string {
+	if len(suggestions) == 0 {
+		return ""
+	}
+	return ": did you mean " + suggest.JoinOr(suggestions) + "?"
+}
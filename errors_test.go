@@ -0,0 +1,258 @@
+package gommand_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
+)
+
+func TestParseErrorPositions(t *testing.T) {
+	t.Run("user alias", func(t *testing.T) {
+		root := &gommand.Command{
+			Name:        "mytool",
+			UserAliases: gommand.AliasMap{"dp": "deploy --env prod"},
+		}
+		root.SubCommand(&gommand.Command{
+			Name:    "deploy",
+			FlagSet: flags.NewFlagSet().AddFlags(flags.StringFlag("env", "", "")),
+			Run:     func(*gommand.Context) error { return nil },
+		})
+		err := root.ExecuteArgs(context.Background(), []string{"dp", "--bogus"})
+		var target gommand.ErrUnknownFlag
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrUnknownFlag, got %T: %v", err, err)
+		}
+		if target.Position != 1 {
+			t.Errorf("got position %d, want 1", target.Position)
+		}
+	})
+
+	t.Run("response file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "args.txt")
+		if err := os.WriteFile(path, []byte("--port eighty"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		root := &gommand.Command{
+			Name:    "mytool",
+			FlagSet: flags.NewFlagSet().AddFlags(flags.BoolFlag("verbose", false, ""), flags.IntFlag("port", 0, "")),
+			Run:     func(*gommand.Context) error { return nil },
+		}
+		err := root.ExecuteArgs(context.Background(), []string{"--verbose", "@" + path}, gommand.WithResponseFiles(1))
+		var target gommand.ErrInvalidFlagValue
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrInvalidFlagValue, got %T: %v", err, err)
+		}
+		if target.Position != 1 || target.Token != "eighty" {
+			t.Errorf("got position %d, token %q, want 1, %q", target.Position, target.Token, "eighty")
+		}
+	})
+}
+
+func TestParseErrors(t *testing.T) {
+	t.Run("unknown flag", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{
+			Name:    "run",
+			FlagSet: flags.NewFlagSet().AddFlags(flags.IntFlag("port", 0, "")),
+			Run:     func(ctx *gommand.Context) error { return nil },
+		})
+
+		err := root.ExecuteArgs(context.Background(), []string{"run", "--prot", "80"})
+		var target gommand.ErrUnknownFlag
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrUnknownFlag, got %T: %v", err, err)
+		}
+		want := gommand.Location{CommandPath: "mytool run", Position: 1}
+		if target.Location != want {
+			t.Errorf("Location = %+v, want %+v", target.Location, want)
+		}
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool [command]", SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{Name: "server", Run: func(ctx *gommand.Context) error { return nil }})
+
+		err := root.ExecuteArgs(context.Background(), []string{"sever", "run"})
+		var target gommand.ErrUnknownCommand
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrUnknownCommand, got %T: %v", err, err)
+		}
+		want := gommand.Location{CommandPath: "mytool", Position: 0}
+		if target.Location != want {
+			t.Errorf("Location = %+v, want %+v", target.Location, want)
+		}
+	})
+
+	t.Run("invalid flag value", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{
+			Name:    "run",
+			FlagSet: flags.NewFlagSet().AddFlags(flags.IntFlag("port", 0, "")),
+			Run:     func(ctx *gommand.Context) error { return nil },
+		})
+
+		err := root.ExecuteArgs(context.Background(), []string{"run", "--port", "eighty"})
+		var target gommand.ErrInvalidFlagValue
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrInvalidFlagValue, got %T: %v", err, err)
+		}
+		want := gommand.Location{CommandPath: "mytool run", Position: 2}
+		if target.Location != want {
+			t.Errorf("Location = %+v, want %+v", target.Location, want)
+		}
+		if target.Flag.Name() != "port" || target.Token != "eighty" || target.Value != "eighty" {
+			t.Errorf("got flag %s, token %q, value %q", target.Flag.Name(), target.Token, target.Value)
+		}
+		if !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("expected wrapped strconv.ErrSyntax, got %v", err)
+		}
+	})
+
+	t.Run("invalid flag value in multi-flag", func(t *testing.T) {
+		cmd := &gommand.Command{
+			Name:         "run",
+			FlagSet:      flags.NewFlagSet().AddFlags(flags.BoolFlagS("verbose", 'v', false, ""), flags.IntFlagS("port", 'p', 0, "")),
+			SilenceHelp:  true,
+			SilenceError: true,
+			Run:          func(ctx *gommand.Context) error { return nil },
+		}
+
+		err := cmd.ExecuteArgs(context.Background(), []string{"-vpx"})
+		var target gommand.ErrInvalidFlagValue
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrInvalidFlagValue, got %T: %v", err, err)
+		}
+		if target.Flag.Name() != "port" || target.Token != "-vpx" || target.Value != "x" {
+			t.Errorf("got flag %s, token %q, value %q", target.Flag.Name(), target.Token, target.Value)
+		}
+	})
+
+	t.Run("missing value in multi-flag", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{
+			Name:    "run",
+			FlagSet: flags.NewFlagSet().AddFlags(flags.BoolFlagS("verbose", 'v', false, ""), flags.IntFlagS("port", 'p', 0, "")),
+			Run:     func(ctx *gommand.Context) error { return nil },
+		})
+
+		err := root.ExecuteArgs(context.Background(), []string{"run", "-vp"})
+		var target gommand.ErrInvalidFlagValue
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrInvalidFlagValue, got %T: %v", err, err)
+		}
+		want := gommand.Location{CommandPath: "mytool run", Position: 1}
+		if target.Location != want {
+			t.Errorf("Location = %+v, want %+v", target.Location, want)
+		}
+		if target.Flag.Name() != "port" || target.Token != "-vp" || target.Value != "" {
+			t.Errorf("got flag %s, token %q, value %q", target.Flag.Name(), target.Token, target.Value)
+		}
+		if got, want := err.Error(), "gommand: missing value for flag --port"; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	})
+
+	t.Run("ambiguous flag", func(t *testing.T) {
+		root := &gommand.Command{
+			Name:              "mytool",
+			PrefixMatching:    true,
+			PersistentFlagSet: flags.NewFlagSet().AddFlags(flags.BoolFlag("dry-run", false, "")),
+			SilenceHelp:       true,
+			SilenceError:      true,
+		}
+		root.SubCommand(&gommand.Command{
+			Name:    "run",
+			FlagSet: flags.NewFlagSet().AddFlags(flags.BoolFlagS("verbose", 'v', false, ""), flags.BoolFlag("debug", false, "")),
+			Run:     func(ctx *gommand.Context) error { return nil },
+		})
+
+		err := root.ExecuteArgs(context.Background(), []string{"run", "-v", "--d"})
+		var target gommand.ErrAmbiguousFlag
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrAmbiguousFlag, got %T: %v", err, err)
+		}
+		want := gommand.Location{CommandPath: "mytool run", Position: 2}
+		if target.Location != want {
+			t.Errorf("Location = %+v, want %+v", target.Location, want)
+		}
+		if target.Prefix != "d" || !slices.Equal(target.Matches, []string{"debug", "dry-run"}) {
+			t.Errorf("got prefix %q, matches %v", target.Prefix, target.Matches)
+		}
+		if !errors.As(err, new(flags.ErrAmbiguousFlag)) {
+			t.Error("expected wrapped flags.ErrAmbiguousFlag")
+		}
+		if want := "gommand: ambiguous flag: --d: did you mean --debug or --dry-run?"; err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("value assigned to boolean multi-flag", func(t *testing.T) {
+		cmd := &gommand.Command{
+			Name:         "run",
+			FlagSet:      flags.NewFlagSet().AddFlags(flags.BoolFlagS("verbose", 'v', false, ""), flags.BoolFlagS("debug", 'd', false, "")),
+			SilenceHelp:  true,
+			SilenceError: true,
+			Run:          func(ctx *gommand.Context) error { return nil },
+		}
+
+		err := cmd.ExecuteArgs(context.Background(), []string{"-vd=true"})
+		var target gommand.ErrInvalidFlagValue
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrInvalidFlagValue, got %T: %v", err, err)
+		}
+		if target.Flag.Name() != "debug" || target.Token != "-vd=true" {
+			t.Errorf("got flag %s, token %q", target.Flag.Name(), target.Token)
+		}
+	})
+
+	t.Run("invalid args", func(t *testing.T) {
+		errValidator := errors.New("validator error")
+		root := &gommand.Command{Name: "mytool", SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{
+			Name:         "run",
+			ArgValidator: func(s []string) error { return errValidator },
+			Run:          func(ctx *gommand.Context) error { return nil },
+		})
+
+		err := root.ExecuteArgs(context.Background(), []string{"run", "a", "b"})
+		var target gommand.ErrInvalidArgs
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrInvalidArgs, got %T: %v", err, err)
+		}
+		want := gommand.Location{CommandPath: "mytool run", Position: -1}
+		if target.Location != want {
+			t.Errorf("Location = %+v, want %+v", target.Location, want)
+		}
+		if !slices.Equal(target.Args, []string{"a", "b"}) {
+			t.Errorf("Args = %v, want [a b]", target.Args)
+		}
+		if !errors.Is(err, errValidator) {
+			t.Errorf("expected wrapped validator error, got %v", err)
+		}
+	})
+
+	t.Run("misordered args", func(t *testing.T) {
+		root := &gommand.Command{Name: "math", ArgValidator: gommand.ArgsAny(), SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{Name: "sum", Run: func(ctx *gommand.Context) error { return nil }})
+
+		err := root.ExecuteArgs(context.Background(), []string{"1", "sum", "2"})
+		var target gommand.ErrMisorderedArgs
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrMisorderedArgs, got %T: %v", err, err)
+		}
+		want := gommand.Location{CommandPath: "math", Position: 1}
+		if target.Location != want {
+			t.Errorf("Location = %+v, want %+v", target.Location, want)
+		}
+		if target.Command != "sum" || !slices.Equal(target.Args, []string{"1"}) {
+			t.Errorf("got command %q, args %v", target.Command, target.Args)
+		}
+	})
+}
@@ -12,7 +12,7 @@ var rootCmd = &gommand.Command{
 	Name:         "sum  [...n]",
 	Usage:        "sum all provided numbers",
 	Version:      "1.0.0",
-	ArgValidator: gommand.ArgsEvery(gommand.ArgsMin(1), ints),
+	ArgValidator: gommand.ArgsEvery(gommand.ArgsMin(1), gommand.ArgsInt()),
 	Run: func(ctx *gommand.Context) error {
 		var total int
 		for _, s := range ctx.Args() {
@@ -27,15 +27,6 @@ var rootCmd = &gommand.Command{
 	},
 }
 
-func ints(s []string) error {
-	for _, arg := range s {
-		if _, err := strconv.Atoi(arg); err != nil {
-			return fmt.Errorf("%s is not an integer", arg)
-		}
-	}
-	return nil
-}
-
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
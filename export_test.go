@@ -0,0 +1,8 @@
+package gommand
+
+import "context"
+
+// ReplComplete exposes replComplete to the tests
+func (c *Command) ReplComplete(line string) []string {
+	return c.replComplete(&Context{Context: context.Background(), cmd: c}, line)
+}
@@ -0,0 +1,65 @@
+package flags
+
+var _ Flag = (*aliasFlag)(nil)
+
+// aliasFlag is a flag that forwards any value set on it to another flag.
+// It is typically used to keep an old flag name working after a rename.
+//
+//	fs.AddFlags(
+//		listen,
+//		flags.AliasFlag("addr", listen).Deprecated("use --listen instead"),
+//	)
+type aliasFlag struct {
+	*baseFlag
+
+	target Flag
+}
+
+func (f *aliasFlag) Type() FlagType                  { return f.target.Type() }
+func (f *aliasFlag) Value() any                      { return f.target.Value() }
+func (f *aliasFlag) IsSet() bool                     { return f.target.IsSet() }
+func (f *aliasFlag) Set(s string) error              { return f.target.Set(s) }
+func (f *aliasFlag) negatable() bool                 { return Negatable(f.target) }
+func (f *aliasFlag) setImplicit(value string)        { Implicit(f.target, value) }
+func (f *aliasFlag) implicitValue() (string, bool)   { return ImplicitValue(f.target) }
+func (f *aliasFlag) setCompletion(fn CompletionFunc) { Completion(f.target, fn) }
+func (f *aliasFlag) completer() CompletionFunc       { return Completer(f.target) }
+func (f *aliasFlag) increment() error                { return Increment(f.target) }
+func (f *aliasFlag) reset()                          { Reset(f.target) }
+func (f *aliasFlag) setValues(values []string) error { return SetValues(f.target, values) }
+
+func (f *aliasFlag) Required() Flag {
+	f.req = true
+	return f
+}
+
+func (f *aliasFlag) Hidden() Flag {
+	f.hidden = true
+	return f
+}
+
+func (f *aliasFlag) Deprecated(msg string) Flag {
+	f.deprecated = msg
+	return f
+}
+
+func (f *aliasFlag) AddSources(sources ...Valuer) Flag {
+	f.addSources(sources...)
+	return f
+}
+
+// AliasFlag returns a flag called name that sets target whenever it is set.
+func AliasFlag(name string, target Flag) Flag {
+	return &aliasFlag{
+		baseFlag: &baseFlag{name: name, usage: "alias for --" + target.Name()},
+		target:   target,
+	}
+}
+
+// AliasFlagS is like AliasFlag, but with a shorthand
+func AliasFlagS(name string, shorthand rune, target Flag) Flag {
+	return &aliasFlag{
+		baseFlag: &baseFlag{name: name, short: shorthand, usage: "alias for --" + target.Name()},
+		target:   target,
+	}
+}
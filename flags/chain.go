@@ -0,0 +1,66 @@
+package flags
+
+// The flagger template only generates the Required and AddSources chain methods, so the
+// other chain methods of Flag are written out here for each of the generated flag types.
+
+func (f *stringFlag) Hidden() Flag        { f.hidden = true; return f }
+func (f *boolFlag) Hidden() Flag          { f.hidden = true; return f }
+func (f *durationFlag) Hidden() Flag      { f.hidden = true; return f }
+func (f *intFlag) Hidden() Flag           { f.hidden = true; return f }
+func (f *int8Flag) Hidden() Flag          { f.hidden = true; return f }
+func (f *int16Flag) Hidden() Flag         { f.hidden = true; return f }
+func (f *int32Flag) Hidden() Flag         { f.hidden = true; return f }
+func (f *int64Flag) Hidden() Flag         { f.hidden = true; return f }
+func (f *uintFlag) Hidden() Flag          { f.hidden = true; return f }
+func (f *uint8Flag) Hidden() Flag         { f.hidden = true; return f }
+func (f *uint16Flag) Hidden() Flag        { f.hidden = true; return f }
+func (f *uint32Flag) Hidden() Flag        { f.hidden = true; return f }
+func (f *uint64Flag) Hidden() Flag        { f.hidden = true; return f }
+func (f *float32Flag) Hidden() Flag       { f.hidden = true; return f }
+func (f *float64Flag) Hidden() Flag       { f.hidden = true; return f }
+func (f *stringSliceFlag) Hidden() Flag   { f.hidden = true; return f }
+func (f *boolSliceFlag) Hidden() Flag     { f.hidden = true; return f }
+func (f *durationSliceFlag) Hidden() Flag { f.hidden = true; return f }
+func (f *intSliceFlag) Hidden() Flag      { f.hidden = true; return f }
+func (f *int8SliceFlag) Hidden() Flag     { f.hidden = true; return f }
+func (f *int16SliceFlag) Hidden() Flag    { f.hidden = true; return f }
+func (f *int32SliceFlag) Hidden() Flag    { f.hidden = true; return f }
+func (f *int64SliceFlag) Hidden() Flag    { f.hidden = true; return f }
+func (f *uintSliceFlag) Hidden() Flag     { f.hidden = true; return f }
+func (f *uint8SliceFlag) Hidden() Flag    { f.hidden = true; return f }
+func (f *uint16SliceFlag) Hidden() Flag   { f.hidden = true; return f }
+func (f *uint32SliceFlag) Hidden() Flag   { f.hidden = true; return f }
+func (f *uint64SliceFlag) Hidden() Flag   { f.hidden = true; return f }
+func (f *float32SliceFlag) Hidden() Flag  { f.hidden = true; return f }
+func (f *float64SliceFlag) Hidden() Flag  { f.hidden = true; return f }
+
+func (f *stringFlag) Deprecated(msg string) Flag        { f.deprecated = msg; return f }
+func (f *boolFlag) Deprecated(msg string) Flag          { f.deprecated = msg; return f }
+func (f *durationFlag) Deprecated(msg string) Flag      { f.deprecated = msg; return f }
+func (f *intFlag) Deprecated(msg string) Flag           { f.deprecated = msg; return f }
+func (f *int8Flag) Deprecated(msg string) Flag          { f.deprecated = msg; return f }
+func (f *int16Flag) Deprecated(msg string) Flag         { f.deprecated = msg; return f }
+func (f *int32Flag) Deprecated(msg string) Flag         { f.deprecated = msg; return f }
+func (f *int64Flag) Deprecated(msg string) Flag         { f.deprecated = msg; return f }
+func (f *uintFlag) Deprecated(msg string) Flag          { f.deprecated = msg; return f }
+func (f *uint8Flag) Deprecated(msg string) Flag         { f.deprecated = msg; return f }
+func (f *uint16Flag) Deprecated(msg string) Flag        { f.deprecated = msg; return f }
+func (f *uint32Flag) Deprecated(msg string) Flag        { f.deprecated = msg; return f }
+func (f *uint64Flag) Deprecated(msg string) Flag        { f.deprecated = msg; return f }
+func (f *float32Flag) Deprecated(msg string) Flag       { f.deprecated = msg; return f }
+func (f *float64Flag) Deprecated(msg string) Flag       { f.deprecated = msg; return f }
+func (f *stringSliceFlag) Deprecated(msg string) Flag   { f.deprecated = msg; return f }
+func (f *boolSliceFlag) Deprecated(msg string) Flag     { f.deprecated = msg; return f }
+func (f *durationSliceFlag) Deprecated(msg string) Flag { f.deprecated = msg; return f }
+func (f *intSliceFlag) Deprecated(msg string) Flag      { f.deprecated = msg; return f }
+func (f *int8SliceFlag) Deprecated(msg string) Flag     { f.deprecated = msg; return f }
+func (f *int16SliceFlag) Deprecated(msg string) Flag    { f.deprecated = msg; return f }
+func (f *int32SliceFlag) Deprecated(msg string) Flag    { f.deprecated = msg; return f }
+func (f *int64SliceFlag) Deprecated(msg string) Flag    { f.deprecated = msg; return f }
+func (f *uintSliceFlag) Deprecated(msg string) Flag     { f.deprecated = msg; return f }
+func (f *uint8SliceFlag) Deprecated(msg string) Flag    { f.deprecated = msg; return f }
+func (f *uint16SliceFlag) Deprecated(msg string) Flag   { f.deprecated = msg; return f }
+func (f *uint32SliceFlag) Deprecated(msg string) Flag   { f.deprecated = msg; return f }
+func (f *uint64SliceFlag) Deprecated(msg string) Flag   { f.deprecated = msg; return f }
+func (f *float32SliceFlag) Deprecated(msg string) Flag  { f.deprecated = msg; return f }
+func (f *float64SliceFlag) Deprecated(msg string) Flag  { f.deprecated = msg; return f }
@@ -0,0 +1,53 @@
+package flags
+
+import "context"
+
+// CompletionDirective tells the shell how to treat the candidates returned by a completion callback
+type CompletionDirective int
+
+const (
+	// CompleteDefault completes the candidates, falling back to file names if there are none
+	CompleteDefault CompletionDirective = 0
+	// CompleteNoFile completes the candidates and never falls back to file names
+	CompleteNoFile CompletionDirective = 1 << iota
+	// CompleteFilterExt treats the candidates as file extensions, without the leading dot, and
+	// completes file names with one of them. ie: yaml, yml
+	CompleteFilterExt
+	// CompleteFilterDirs completes directory names only. Any candidates are ignored
+	CompleteFilterDirs
+)
+
+// CompletionContext is passed to completion callbacks. It is implemented by *gommand.Context
+// and gives access to the flags and args parsed from the command line being completed.
+type CompletionContext interface {
+	context.Context
+	Args() []string
+	Flags() *FlagGetter
+}
+
+// CompletionFunc returns the candidates for the value of a flag, given the partial value
+// typed so far. Candidates can be followed by a tab and a description, which is shown by
+// shells that support it. ie: "prod\tthe production cluster"
+type CompletionFunc func(ctx CompletionContext, toComplete string) ([]string, CompletionDirective)
+
+// completable is implemented by flags that can have a completion callback
+type completable interface {
+	setCompletion(fn CompletionFunc)
+	completer() CompletionFunc
+}
+
+// Completion sets the function used to complete the value of f in shell completion
+func Completion(f Flag, fn CompletionFunc) Flag {
+	if c, ok := f.(completable); ok {
+		c.setCompletion(fn)
+	}
+	return f
+}
+
+// Completer returns the function used to complete the value of f, or nil if there is none
+func Completer(f Flag) CompletionFunc {
+	if c, ok := f.(completable); ok {
+		return c.completer()
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package flags
+
+import (
+	"strconv"
+)
+
+var _ Flag = (*countFlag)(nil)
+
+// countFlag is an int flag that is incremented every time it appears on
+// the command line without a value. ie: -vvv or --verbose --verbose
+//
+// A value can still be set explicitly, either with --verbose=3 or by one of the
+// flag's sources, in which case that value is used as the starting count.
+type countFlag struct {
+	*baseFlag
+
+	defValue int
+	value    int
+}
+
+func (f *countFlag) Type() FlagType { return CountFlagType }
+
+func (f *countFlag) Value() any {
+	if f.IsSet() {
+		return f.value
+	}
+	return f.defValue
+}
+
+func (f *countFlag) Set(s string) error {
+	v, err := strconv.ParseInt(s, 0, 64)
+	if err != nil {
+		return err
+	}
+	f.value = int(v)
+	f.set = true
+	return nil
+}
+
+// increment adds one to the count. If the flag has not yet been set, the count
+// starts from the value of its sources, or the default if no source has a value.
+func (f *countFlag) increment() error {
+	if !f.set {
+		if err := SetFromSources(f); err != nil {
+			return err
+		}
+		if !f.set {
+			f.value = f.defValue
+		}
+	}
+	f.value++
+	f.set = true
+	return nil
+}
+
+func (f *countFlag) Required() Flag {
+	f.req = true
+	return f
+}
+
+func (f *countFlag) Hidden() Flag {
+	f.hidden = true
+	return f
+}
+
+func (f *countFlag) Deprecated(msg string) Flag {
+	f.deprecated = msg
+	return f
+}
+
+func (f *countFlag) AddSources(sources ...Valuer) Flag {
+	f.addSources(sources...)
+	return f
+}
+
+// incrementer is implemented by flags that can be incremented
+type incrementer interface {
+	increment() error
+}
+
+// Increment increments the value of a count flag by one. It returns
+// ErrInvalidFlagType if f is not a count flag.
+func Increment(f Flag) error {
+	i, ok := f.(incrementer)
+	if !ok {
+		return ErrInvalidFlagType{Flag: f, ExpectedType: CountFlagType}
+	}
+	return i.increment()
+}
+
+func CountFlag(name string, value int, usage string) Flag {
+	return &countFlag{
+		baseFlag: &baseFlag{name: name, usage: usage},
+		defValue: value,
+	}
+}
+
+func CountFlagS(name string, shorthand rune, value int, usage string) Flag {
+	return &countFlag{
+		baseFlag: &baseFlag{name: name, short: shorthand, usage: usage},
+		defValue: value,
+	}
+}
+
+func (fs *FlagSet) Count(name string, value int, usage string) {
+	fs.AddFlag(CountFlag(name, value, usage))
+}
+
+func (fs *FlagSet) CountS(name string, shorthand rune, value int, usage string) {
+	fs.AddFlag(CountFlagS(name, shorthand, value, usage))
+}
+
+func (fs *FlagSet) countVal(name string) (int, error) {
+	f, err := fs.flag(name, CountFlagType)
+	if err != nil {
+		return 0, err
+	}
+	return f.Value().(int), nil
+}
+
+func (g FlagGetter) LookupCount(name string) (int, error) {
+	return g.fs.countVal(name)
+}
+
+func (g FlagGetter) Count(name string) int {
+	v, _ := g.LookupCount(name)
+	return v
+}
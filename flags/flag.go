@@ -1,7 +1,9 @@
 package flags
 
 import (
+	"fmt"
 	"os"
+	"reflect"
 	"strings"
 )
 
@@ -21,6 +23,8 @@ type Flag interface {
 	Name() string
 	Short() rune
 	Usage() string
+	// IsSet reports whether the flag was explicitly given a value, either on the
+	// command line or by one of its Sources. When false, Value returns the default.
 	IsSet() bool
 	IsRequired() bool
 	Value() any
@@ -29,6 +33,12 @@ type Flag interface {
 
 	AddSources(sources ...Valuer) Flag
 	Required() Flag
+	// Hidden hides the flag from help text, generated docs and completions.
+	// Hidden flags can still be set on the command line.
+	Hidden() Flag
+	// Deprecated marks the flag as deprecated. msg is printed as a warning when the flag
+	// is used, and should tell the user what to use instead. ie: use --listen instead
+	Deprecated(msg string) Flag
 
 	Set(string) error
 }
@@ -46,11 +56,16 @@ func Stringer(flag Flag, nameLen int, hasShort bool) string {
 		}
 	}
 
+	name := DisplayName(flag)
 	sb.WriteString("--")
-	sb.WriteString(flag.Name())
-	sb.WriteString(strings.Repeat(" ", nameLen-len(flag.Name())))
+	sb.WriteString(name)
+	sb.WriteString(strings.Repeat(" ", nameLen-len(name)))
 	sb.WriteString("  ")
 	sb.WriteString(flag.Usage())
+	if flag.Type() == CountFlagType {
+		sb.WriteString(" ")
+		sb.WriteString("(count)")
+	}
 	if flag.IsRequired() {
 		sb.WriteString(" ")
 		sb.WriteString("(required)")
@@ -58,6 +73,19 @@ func Stringer(flag Flag, nameLen int, hasShort bool) string {
 	return sb.String()
 }
 
+// DisplayName returns the name of the flag as it is rendered in help text, without the leading dashes
+// ie: [no-]cache or color[=STRING]
+func DisplayName(f Flag) string {
+	name := f.Name()
+	if Negatable(f) {
+		name = "[" + negationPrefix + "]" + name
+	}
+	if _, ok := ImplicitValue(f); ok {
+		name += "[=" + strings.ToUpper(strings.TrimSuffix(f.Type().String(), "FlagType")) + "]"
+	}
+	return name
+}
+
 func SetFromSources(f Flag) error {
 	for _, source := range f.Sources() {
 		if val, ok := source.Value(f.Name()); ok {
@@ -70,20 +98,134 @@ func SetFromSources(f Flag) error {
 	return nil
 }
 
+// hideable is implemented by flags that can be hidden
+type hideable interface {
+	isHidden() bool
+}
+
+// IsHidden reports whether f is hidden from help text, generated docs and completions.
+// Deprecated flags are always hidden.
+func IsHidden(f Flag) bool {
+	h, ok := f.(hideable)
+	return ok && h.isHidden()
+}
+
+// deprecatable is implemented by flags that can be deprecated
+type deprecatable interface {
+	deprecation() string
+}
+
+// Deprecation returns the deprecation message of f, or an empty string if f is not deprecated
+func Deprecation(f Flag) string {
+	if d, ok := f.(deprecatable); ok {
+		return d.deprecation()
+	}
+	return ""
+}
+
+// implicitValuer is implemented by flags that can have an implicit value
+type implicitValuer interface {
+	setImplicit(value string)
+	implicitValue() (string, bool)
+}
+
+// Implicit sets the value used when f is provided without one. ie: --color
+// A flag with an implicit value will only accept an explicit value through `=`,
+// ie: --color=always, never by consuming the next argument.
+func Implicit(f Flag, value string) Flag {
+	if i, ok := f.(implicitValuer); ok {
+		i.setImplicit(value)
+	}
+	return f
+}
+
+// ImplicitValue returns the value used when f is provided without one,
+// and whether f has an implicit value at all.
+func ImplicitValue(f Flag) (string, bool) {
+	if i, ok := f.(implicitValuer); ok {
+		return i.implicitValue()
+	}
+	return "", false
+}
+
+// Format returns the value of f as a string that Set accepts. ie: a,b for a string slice
+func Format(f Flag) string {
+	return strings.Join(formatValues(f), sliceSeparator)
+}
+
+// formatValues returns the elements of the value of f as strings, or the value itself
+// if f is not a slice flag
+func formatValues(f Flag) []string {
+	v := reflect.ValueOf(f.Value())
+	if v.Kind() != reflect.Slice {
+		return []string{fmt.Sprint(f.Value())}
+	}
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(v.Index(i))
+	}
+	return parts
+}
+
+// Snapshot saves the value of f and whether it is set, and returns a function restoring
+// them. ie: to parse a command line without keeping the values it sets
+func Snapshot(f Flag) (restore func() error) {
+	set, values := f.IsSet(), formatValues(f)
+	return func() error {
+		if !set {
+			if _, ok := f.(resetter); !ok {
+				return nil
+			}
+			// the value is restored too, so that it is not left
+			// behind, even though it is not used while unset
+			defer Reset(f)
+		}
+		if IsSliceFlag(f) {
+			return SetValues(f, values)
+		}
+		return f.Set(values[0])
+	}
+}
+
+// resetter is implemented by flags that can be reset
+type resetter interface {
+	reset()
+}
+
+// Reset returns f to its default value, as if it had never been set
+func Reset(f Flag) {
+	if r, ok := f.(resetter); ok {
+		r.reset()
+	}
+}
+
 type baseFlag struct {
-	name    string
-	short   rune
-	usage   string
-	set     bool
-	req     bool
-	sources []Valuer
-}
-
-func (f *baseFlag) Type() FlagType               { return UnknownFlagType }
-func (f *baseFlag) Name() string                 { return f.name }
-func (f *baseFlag) Short() rune                  { return f.short }
-func (f *baseFlag) Usage() string                { return f.usage }
-func (f *baseFlag) IsSet() bool                  { return f.set }
-func (f *baseFlag) IsRequired() bool             { return f.req }
-func (f *baseFlag) Sources() []Valuer            { return f.sources }
-func (f *baseFlag) addSources(sources ...Valuer) { f.sources = append(f.sources, sources...) }
+	name        string
+	short       rune
+	usage       string
+	set         bool
+	req         bool
+	hidden      bool
+	deprecated  string
+	sources     []Valuer
+	implicit    string
+	hasImplicit bool
+	completion  CompletionFunc
+}
+
+func (f *baseFlag) Type() FlagType                  { return UnknownFlagType }
+func (f *baseFlag) Name() string                    { return f.name }
+func (f *baseFlag) Short() rune                     { return f.short }
+func (f *baseFlag) Usage() string                   { return f.usage }
+func (f *baseFlag) IsSet() bool                     { return f.set }
+func (f *baseFlag) IsRequired() bool                { return f.req }
+func (f *baseFlag) Sources() []Valuer               { return f.sources }
+func (f *baseFlag) addSources(sources ...Valuer)    { f.sources = append(f.sources, sources...) }
+func (f *baseFlag) isHidden() bool                  { return f.hidden || f.deprecated != "" }
+func (f *baseFlag) deprecation() string             { return f.deprecated }
+func (f *baseFlag) negatable() bool                 { return false }
+func (f *baseFlag) setImplicit(value string)        { f.implicit, f.hasImplicit = value, true }
+func (f *baseFlag) implicitValue() (string, bool)   { return f.implicit, f.hasImplicit }
+func (f *baseFlag) setCompletion(fn CompletionFunc) { f.completion = fn }
+func (f *baseFlag) completer() CompletionFunc       { return f.completion }
+func (f *baseFlag) reset()                          { f.set = false }
@@ -1,10 +1,14 @@
 package flags
 
 import (
+	"errors"
 	"fmt"
 	"maps"
+	"slices"
 	"sort"
 	"strings"
+
+	"github.com/jimmykodes/gommand/internal/suggest"
 )
 
 type ErrInvalidFlagType struct {
@@ -32,6 +36,33 @@ func (e ErrMissingRequiredFlag) Error() string {
 	return fmt.Sprintf("gommand: missing required flag: --%s", e.Flag.Name())
 }
 
+type ErrAmbiguousFlag struct {
+	Prefix  string
+	Matches []string
+}
+
+func (e ErrAmbiguousFlag) Error() string {
+	matches := make([]string, len(e.Matches))
+	for i, m := range e.Matches {
+		matches[i] = "--" + m
+	}
+	return fmt.Sprintf("gommand: ambiguous flag: --%s: did you mean %s?", e.Prefix, suggest.JoinOr(matches))
+}
+
+// ErrFlagConflict is returned by FlagSet.Validate when two flags in the set share a
+// name or shorthand
+type ErrFlagConflict struct {
+	Flag     Flag
+	Conflict Flag
+}
+
+func (e ErrFlagConflict) Error() string {
+	if e.Flag.Name() == e.Conflict.Name() {
+		return fmt.Sprintf("gommand: flag --%s defined more than once", e.Flag.Name())
+	}
+	return fmt.Sprintf("gommand: flags --%s and --%s share shorthand -%c", e.Flag.Name(), e.Conflict.Name(), e.Flag.Short())
+}
+
 func NewFlagSet(options ...FlagSetOption) *FlagSet {
 	f := &FlagSet{flags: make(map[string]Flag), shortFlags: make(map[rune]Flag)}
 	for _, option := range options {
@@ -44,10 +75,51 @@ type FlagSet struct {
 	flags      map[string]Flag
 	shortFlags map[rune]Flag
 	sources    []Valuer
+
+	// added is every flag added with AddFlag, in order, including any
+	// that were later overwritten by a flag with the same name or shorthand
+	added []Flag
 }
 
+// FromName returns the flag with the given name. If no flag is registered with
+// that name, but name is the negated form of a negatable flag, ie: no-cache,
+// a flag is returned that sets the negatable flag to the inverse of any value set on it.
 func (fs *FlagSet) FromName(name string) Flag {
-	return fs.flags[name]
+	if f, ok := fs.flags[name]; ok {
+		return f
+	}
+	return fs.fromNegatedName(name)
+}
+
+// FromPrefix returns the flag whose name, or negated name, is uniquely identified by prefix.
+// An exact match always takes precedence. Hidden and deprecated flags only match by their
+// full name. If more than one flag matches, ErrAmbiguousFlag is returned. If no flag matches,
+// or prefix is empty, both return values are nil.
+func (fs *FlagSet) FromPrefix(prefix string) (Flag, error) {
+	if f := fs.FromName(prefix); f != nil || prefix == "" {
+		return f, nil
+	}
+	var matches []string
+	for name, f := range fs.flags {
+		if IsHidden(f) {
+			continue
+		}
+		if strings.HasPrefix(name, prefix) {
+			matches = append(matches, name)
+		}
+		if Negatable(f) && strings.HasPrefix(negationPrefix+name, prefix) {
+			matches = append(matches, negationPrefix+name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return fs.FromName(matches[0]), nil
+	default:
+		sort.Strings(matches)
+		return nil, ErrAmbiguousFlag{Prefix: prefix, Matches: matches}
+	}
 }
 
 func (fs *FlagSet) FromShort(short rune) Flag {
@@ -55,6 +127,7 @@ func (fs *FlagSet) FromShort(short rune) Flag {
 }
 
 func (fs *FlagSet) AddFlag(f Flag) *FlagSet {
+	fs.added = append(fs.added, f)
 	fs.flags[f.Name()] = f
 	if f.Short() != 0 {
 		fs.shortFlags[f.Short()] = f
@@ -76,30 +149,39 @@ func (fs *FlagSet) AddSource(source Valuer) *FlagSet {
 }
 
 func (fs *FlagSet) Repr() string {
+	return Usages(slices.Collect(maps.Values(fs.flags)))
+}
+
+// Usages returns the usage line of each of the flags that is not hidden, sorted by name
+// and aligned into columns, as it is printed in a command's help text.
+func Usages(flags []Flag) string {
 	var (
-		names    = make([]string, 0, len(fs.flags))
+		visible  = make([]Flag, 0, len(flags))
 		maxLen   = 0
 		hasShort = false
 	)
-	for n, flag := range fs.flags {
-		names = append(names, n)
-		if l := len(flag.Name()); l > maxLen {
+	for _, flag := range flags {
+		if IsHidden(flag) {
+			continue
+		}
+		visible = append(visible, flag)
+		if l := len(DisplayName(flag)); l > maxLen {
 			maxLen = l
 		}
 		if flag.Short() != 0 {
 			hasShort = true
 		}
 	}
-	sort.Strings(names)
-	strs := make([]string, len(names))
-	for i, name := range names {
-		strs[i] = Stringer(fs.flags[name], maxLen, hasShort)
+	slices.SortFunc(visible, func(a, b Flag) int { return strings.Compare(a.Name(), b.Name()) })
+	strs := make([]string, len(visible))
+	for i, flag := range visible {
+		strs[i] = Stringer(flag, maxLen, hasShort)
 	}
 	return strings.Join(strs, "\n")
 }
 
 func (fs *FlagSet) addHelpFlag() {
-	fs.BoolS("help", 'h', false, "show this help message")
+	fs.AddFlag(helpFlag{BoolFlagS("help", 'h', false, "show this help message")})
 }
 
 func (fs *FlagSet) AddFlagSet(set *FlagSet) *FlagSet {
@@ -113,6 +195,28 @@ func (fs *FlagSet) AddFlagSet(set *FlagSet) *FlagSet {
 	return fs
 }
 
+// Validate reports any flags added to the set that share a name or shorthand with a
+// flag added before them, which would otherwise silently overwrite it.
+func (fs *FlagSet) Validate() error {
+	var (
+		errs   []error
+		names  = make(map[string]Flag)
+		shorts = make(map[rune]Flag)
+	)
+	for _, f := range fs.added {
+		if other, ok := names[f.Name()]; ok {
+			errs = append(errs, ErrFlagConflict{Flag: f, Conflict: other})
+		} else if other, ok := shorts[f.Short()]; ok && f.Short() != 0 {
+			errs = append(errs, ErrFlagConflict{Flag: f, Conflict: other})
+		}
+		names[f.Name()] = f
+		if f.Short() != 0 {
+			shorts[f.Short()] = f
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (fs *FlagSet) MarkRequired(name string) error {
 	f, ok := fs.flags[name]
 	if !ok {
@@ -3,6 +3,7 @@ package flags_test
 import (
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -301,3 +302,538 @@ func TestFlagSource(t *testing.T) {
 		}
 	})
 }
+
+func TestNegatableFlags(t *testing.T) {
+	t.Run("bool flags are negatable", func(t *testing.T) {
+		fs := flags.NewFlagSet()
+		fs.Bool("cache", true, "cache")
+		fs.String("name", "", "name")
+
+		if f := fs.FromName("no-cache"); f == nil {
+			t.Error("expected --no-cache to resolve")
+		}
+		if f := fs.FromName("no-name"); f != nil {
+			t.Errorf("expected --no-name not to resolve, got %v", f.Name())
+		}
+	})
+
+	t.Run("negated flag inverts values", func(t *testing.T) {
+		tests := []struct {
+			set  string
+			want bool
+		}{
+			{set: "true", want: false},
+			{set: "false", want: true},
+		}
+		for _, tt := range tests {
+			fs := flags.NewFlagSet()
+			fs.Bool("cache", true, "cache")
+			if err := fs.FromName("no-cache").Set(tt.set); err != nil {
+				t.Fatalf("Set(%s): %v", tt.set, err)
+			}
+			fg := flags.NewFlagGetter(fs)
+			if got := fg.Bool("cache"); got != tt.want {
+				t.Errorf("--no-cache=%s: got %v, want %v", tt.set, got, tt.want)
+			}
+		}
+	})
+
+	t.Run("explicit flag takes precedence over negation", func(t *testing.T) {
+		fs := flags.NewFlagSet()
+		fs.Bool("cache", true, "cache")
+		fs.String("no-cache", "", "no cache")
+
+		if f := fs.FromName("no-cache"); f.Type() != flags.StringFlagType {
+			t.Errorf("expected registered --no-cache flag, got %s", f.Type())
+		}
+	})
+
+	t.Run("OptionalBool distinguishes explicit false from default", func(t *testing.T) {
+		fs := flags.NewFlagSet()
+		fs.Bool("cache", false, "cache")
+		fg := flags.NewFlagGetter(fs)
+
+		if got := fg.OptionalBool("cache"); got != nil {
+			t.Errorf("expected nil before set, got %v", *got)
+		}
+		if err := fs.FromName("no-cache").Set("true"); err != nil {
+			t.Fatalf("Set: %v", err)
+		}
+		if got := fg.OptionalBool("cache"); got == nil || *got {
+			t.Errorf("expected explicit false, got %v", got)
+		}
+	})
+
+	t.Run("Repr renders negation", func(t *testing.T) {
+		fs := flags.NewFlagSet(flags.WithHelpFlag())
+		fs.Bool("cache", true, "use the cache")
+		fs.String("name", "", "the name")
+
+		want := strings.Join([]string{
+			"      --[no-]cache  use the cache",
+			"  -h, --help        show this help message",
+			"      --name        the name",
+		}, "\n")
+		if got := fs.Repr(); got != want {
+			t.Errorf("Repr():\n%s\nwant:\n%s", got, want)
+		}
+	})
+}
+
+func TestCountFlag(t *testing.T) {
+	t.Run("increment counts from default", func(t *testing.T) {
+		f := flags.CountFlag("verbose", 1, "verbose")
+		for range 3 {
+			if err := flags.Increment(f); err != nil {
+				t.Fatalf("Increment: %v", err)
+			}
+		}
+		if got := f.Value().(int); got != 4 {
+			t.Errorf("got %d, want 4", got)
+		}
+	})
+
+	t.Run("increment is seeded from sources", func(t *testing.T) {
+		f := flags.CountFlag("verbose", 0, "verbose").
+			AddSources(flags.ValuerFunc(func(string) (string, bool) { return "2", true }))
+		if err := flags.Increment(f); err != nil {
+			t.Fatalf("Increment: %v", err)
+		}
+		if got := f.Value().(int); got != 3 {
+			t.Errorf("got %d, want 3", got)
+		}
+	})
+
+	t.Run("getter resolves sources", func(t *testing.T) {
+		fs := flags.NewFlagSet().
+			AddSource(flags.ValuerFunc(func(string) (string, bool) { return "2", true }))
+		fs.CountS("verbose", 'v', 0, "verbose")
+		if got := flags.NewFlagGetter(fs).Count("verbose"); got != 2 {
+			t.Errorf("got %d, want 2", got)
+		}
+	})
+
+	t.Run("increment non-count flag is error", func(t *testing.T) {
+		err := flags.Increment(flags.IntFlag("num", 0, "num"))
+		var target flags.ErrInvalidFlagType
+		if !errors.As(err, &target) {
+			t.Errorf("expected ErrInvalidFlagType, got %T: %v", err, err)
+		}
+	})
+
+	t.Run("Repr renders count", func(t *testing.T) {
+		fs := flags.NewFlagSet()
+		fs.CountS("verbose", 'v', 0, "verbosity")
+		if got, want := fs.Repr(), "  -v, --verbose  verbosity (count)"; got != want {
+			t.Errorf("Repr(): got %q, want %q", got, want)
+		}
+	})
+}
+
+func TestImplicitValue(t *testing.T) {
+	t.Run("ImplicitValue reports implicit value", func(t *testing.T) {
+		f := flags.StringFlag("color", "never", "color")
+		if _, ok := flags.ImplicitValue(f); ok {
+			t.Error("expected no implicit value")
+		}
+		flags.Implicit(f, "always")
+		if v, ok := flags.ImplicitValue(f); !ok || v != "always" {
+			t.Errorf("got %q, %v, want %q, true", v, ok, "always")
+		}
+	})
+
+	t.Run("Repr renders optional value syntax", func(t *testing.T) {
+		fs := flags.NewFlagSet().AddFlags(
+			flags.Implicit(flags.StringFlag("color", "never", "when to color output"), "always"),
+			flags.StringFlag("name", "", "the name"),
+		)
+		want := strings.Join([]string{
+			"  --color[=STRING]  when to color output",
+			"  --name            the name",
+		}, "\n")
+		if got := fs.Repr(); got != want {
+			t.Errorf("Repr():\n%s\nwant:\n%s", got, want)
+		}
+	})
+}
+
+func TestFlagSet_FromPrefix(t *testing.T) {
+	fs := flags.NewFlagSet()
+	fs.Bool("verbose", false, "")
+	fs.Bool("version-check", false, "")
+	fs.String("listen", "", "")
+	fs.String("list", "", "")
+	fs.AddFlags(
+		flags.StringFlag("listener", "", "").Hidden(),
+		flags.BoolFlag("verbosity", false, "").Deprecated("use --verbose"),
+	)
+
+	tests := []struct {
+		prefix  string
+		want    string
+		wantErr bool
+	}{
+		{prefix: "verb", want: "verbose"},
+		{prefix: "list", want: "list"},
+		{prefix: "liste", want: "listen"},
+		{prefix: "no-verb", want: "no-verbose"},
+		{prefix: "ver", wantErr: true},
+		{prefix: "li", wantErr: true},
+		{prefix: "missing"},
+		{prefix: ""},
+		{prefix: "listener", want: "listener"},
+		{prefix: "verbosity", want: "verbosity"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			f, err := fs.FromPrefix(tt.prefix)
+			if tt.wantErr {
+				var target flags.ErrAmbiguousFlag
+				if !errors.As(err, &target) {
+					t.Fatalf("expected ErrAmbiguousFlag, got %T: %v", err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.want == "" {
+				if f != nil {
+					t.Errorf("expected no match, got %s", f.Name())
+				}
+				return
+			}
+			if f == nil || f.Name() != tt.want {
+				t.Errorf("got %v, want %s", f, tt.want)
+			}
+		})
+	}
+
+	t.Run("ambiguous error lists matches", func(t *testing.T) {
+		_, err := fs.FromPrefix("ver")
+		want := "gommand: ambiguous flag: --ver: did you mean --verbose or --version-check?"
+		if err == nil || err.Error() != want {
+			t.Errorf("got %v, want %s", err, want)
+		}
+	})
+}
+
+func TestHiddenFlags(t *testing.T) {
+	fs := flags.NewFlagSet().AddFlags(
+		flags.StringFlag("legacy", "", "legacy flag").Hidden(),
+		flags.StringFlag("name", "", "the name"),
+	)
+
+	if got, want := fs.Repr(), "  --name  the name"; got != want {
+		t.Errorf("Repr(): got %q, want %q", got, want)
+	}
+	if f := fs.FromName("legacy"); f == nil || !flags.IsHidden(f) {
+		t.Errorf("expected hidden flag to be registered")
+	}
+}
+
+func TestAliasFlag(t *testing.T) {
+	listen := flags.StringFlag("listen", "", "listen address")
+	fs := flags.NewFlagSet().AddFlags(
+		listen,
+		flags.AliasFlagS("addr", 'a', listen).Deprecated("use --listen instead"),
+	)
+
+	addr := fs.FromShort('a')
+	if addr == nil || addr.Name() != "addr" {
+		t.Fatalf("expected alias to be registered by shorthand")
+	}
+	if !flags.IsHidden(addr) || flags.Deprecation(addr) != "use --listen instead" {
+		t.Errorf("expected deprecated alias to be hidden, got hidden=%v deprecation=%q", flags.IsHidden(addr), flags.Deprecation(addr))
+	}
+	if flags.IsHidden(listen) || flags.Deprecation(listen) != "" {
+		t.Error("deprecating the alias should not deprecate the target")
+	}
+	if err := addr.Set(":8080"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if got := flags.NewFlagGetter(fs).String("listen"); got != ":8080" {
+		t.Errorf("got %q, want %q", got, ":8080")
+	}
+	if !addr.IsSet() || addr.Type() != flags.StringFlagType {
+		t.Errorf("expected alias to reflect its target")
+	}
+}
+
+func TestFlagSet_Validate(t *testing.T) {
+	tests := []struct {
+		name  string
+		flags []flags.Flag
+		want  []string
+	}{
+		{
+			name:  "no conflicts",
+			flags: []flags.Flag{flags.StringFlagS("output", 'o', "", ""), flags.BoolFlag("overwrite", false, "")},
+		},
+		{
+			name:  "duplicate name",
+			flags: []flags.Flag{flags.StringFlag("output", "", ""), flags.BoolFlag("output", false, "")},
+			want:  []string{"gommand: flag --output defined more than once"},
+		},
+		{
+			name:  "duplicate shorthand",
+			flags: []flags.Flag{flags.StringFlagS("output", 'o', "", ""), flags.BoolFlagS("overwrite", 'o', false, "")},
+			want:  []string{"gommand: flags --overwrite and --output share shorthand -o"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := flags.NewFlagSet().AddFlags(tt.flags...).Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var target flags.ErrFlagConflict
+			if !errors.As(err, &target) {
+				t.Fatalf("expected ErrFlagConflict, got %T: %v", err, err)
+			}
+			if got := err.Error(); got != strings.Join(tt.want, "\n") {
+				t.Errorf("got %q, want %q", got, strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestFlagCompletion(t *testing.T) {
+	complete := func(flags.CompletionContext, string) ([]string, flags.CompletionDirective) {
+		return []string{"a", "b"}, flags.CompleteNoFile
+	}
+	target := flags.Completion(flags.StringFlag("listen", "", ""), complete)
+	alias := flags.AliasFlag("addr", target)
+	plain := flags.IntFlag("port", 0, "")
+
+	for _, f := range []flags.Flag{target, alias} {
+		fn := flags.Completer(f)
+		if fn == nil {
+			t.Fatalf("expected --%s to have a completer", f.Name())
+		}
+		if got, directive := fn(nil, ""); !reflect.DeepEqual(got, []string{"a", "b"}) || directive != flags.CompleteNoFile {
+			t.Errorf("--%s completer returned %v, %v", f.Name(), got, directive)
+		}
+	}
+	if flags.Completer(plain) != nil {
+		t.Error("expected flag without completion to have no completer")
+	}
+}
+
+func TestReset(t *testing.T) {
+	port := flags.IntFlag("port", 8080, "")
+	verbose := flags.CountFlagS("verbose", 'v', 0, "")
+	alias := flags.AliasFlag("addr", port)
+	fs := flags.NewFlagSet().AddFlags(port, verbose, alias)
+	fg := flags.NewFlagGetter(fs)
+
+	if err := alias.Set("9090"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := flags.Increment(verbose); err != nil {
+		t.Fatalf("Increment: %v", err)
+	}
+
+	flags.Reset(alias)
+	flags.Reset(verbose)
+	if port.IsSet() || verbose.IsSet() {
+		t.Error("expected flags to be unset after Reset")
+	}
+	if got := fg.Int("port"); got != 8080 {
+		t.Errorf("got port %d, want default 8080", got)
+	}
+	if err := flags.Increment(verbose); err != nil {
+		t.Fatalf("Increment: %v", err)
+	}
+	if got := fg.Count("verbose"); got != 1 {
+		t.Errorf("got verbose %d, want count to restart from the default", got)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	profile := flags.StringFlag("profile", "default", "")
+	tags := flags.StringSliceFlag("tags", nil, "")
+	verbose := flags.CountFlagS("verbose", 'v', 0, "")
+	if err := profile.Set("prod"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := flags.SetValues(tags, []string{"a,b", "c"}); err != nil {
+		t.Fatalf("SetValues: %v", err)
+	}
+
+	var restore []func() error
+	for _, f := range []flags.Flag{profile, tags, verbose} {
+		restore = append(restore, flags.Snapshot(f))
+	}
+	_ = profile.Set("other")
+	_ = tags.Set("d")
+	_ = flags.Increment(verbose)
+	for _, r := range restore {
+		if err := r(); err != nil {
+			t.Fatalf("restore: %v", err)
+		}
+	}
+
+	if got := profile.Value(); got != "prod" {
+		t.Errorf("got profile %v, want prod", got)
+	}
+	if got, want := tags.Value(), []string{"a,b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got tags %q, want %q", got, want)
+	}
+	if verbose.IsSet() {
+		t.Error("expected verbose to be unset")
+	}
+	if err := flags.Increment(verbose); err != nil {
+		t.Fatalf("Increment: %v", err)
+	}
+	if got := verbose.Value(); got != 1 {
+		t.Errorf("got verbose %v, want count to restart from the default", got)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		flag flags.Flag
+		set  string
+		want string
+	}{
+		{name: "default", flag: flags.IntFlag("port", 8080, ""), want: "8080"},
+		{name: "string", flag: flags.StringFlag("name", "", ""), set: "a b", want: "a b"},
+		{name: "bool", flag: flags.BoolFlag("verbose", false, ""), set: "true", want: "true"},
+		{name: "duration", flag: flags.DurationFlag("timeout", 0, ""), set: "90s", want: "1m30s"},
+		{name: "slice", flag: flags.StringSliceFlag("tags", nil, ""), set: "a,b", want: "a,b"},
+		{name: "float slice", flag: flags.Float64SliceFlag("weights", nil, ""), set: "1.5,2", want: "1.5,2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.set != "" {
+				if err := tt.flag.Set(tt.set); err != nil {
+					t.Fatalf("Set: %v", err)
+				}
+			}
+			got := flags.Format(tt.flag)
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if err := tt.flag.Set(got); err != nil {
+				t.Errorf("formatted value not accepted by Set: %v", err)
+			}
+		})
+	}
+}
+
+func TestSetValues(t *testing.T) {
+	tags := flags.StringSliceFlag("tags", nil, "")
+	ports := flags.IntSliceFlag("ports", nil, "")
+	alias := flags.AliasFlag("tag", tags)
+	fs := flags.NewFlagSet().AddFlags(tags, ports, alias)
+	fg := flags.NewFlagGetter(fs)
+
+	if err := flags.SetValues(alias, []string{"a,b", "c"}); err != nil {
+		t.Fatalf("SetValues: %v", err)
+	}
+	if got, want := fg.StringSlice("tags"), []string{"a,b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if err := flags.SetValues(ports, []string{"80", "443"}); err != nil {
+		t.Fatalf("SetValues: %v", err)
+	}
+	if got, want := fg.IntSlice("ports"), []int{80, 443}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if err := flags.SetValues(ports, []string{"80", "http"}); err == nil {
+		t.Error("expected error for invalid element")
+	}
+	if got, want := fg.IntSlice("ports"), []int{80, 443}; !reflect.DeepEqual(got, want) {
+		t.Errorf("invalid element changed the value: got %v, want %v", got, want)
+	}
+	if err := flags.SetValues(flags.IntFlag("port", 0, ""), []string{"80"}); err == nil {
+		t.Error("expected error for non slice flag")
+	}
+
+	sliceFlags := []flags.Flag{
+		flags.StringSliceFlag("a", nil, ""), flags.BoolSliceFlag("a", nil, ""), flags.DurationSliceFlag("a", nil, ""),
+		flags.Float32SliceFlag("a", nil, ""), flags.Float64SliceFlag("a", nil, ""),
+		flags.IntSliceFlag("a", nil, ""), flags.Int8SliceFlag("a", nil, ""), flags.Int16SliceFlag("a", nil, ""),
+		flags.Int32SliceFlag("a", nil, ""), flags.Int64SliceFlag("a", nil, ""),
+		flags.UintSliceFlag("a", nil, ""), flags.Uint8SliceFlag("a", nil, ""), flags.Uint16SliceFlag("a", nil, ""),
+		flags.Uint32SliceFlag("a", nil, ""), flags.Uint64SliceFlag("a", nil, ""),
+	}
+	for _, f := range sliceFlags {
+		if !flags.IsSliceFlag(f) {
+			t.Errorf("expected %s to be a slice flag", f.Type())
+		}
+		value := "1"
+		if f.Type() == flags.DurationSliceFlagType {
+			value = "1s"
+		}
+		if err := f.Set(value); err != nil {
+			t.Fatalf("%s: Set: %v", f.Type(), err)
+		}
+		want := f.Value()
+		flags.Reset(f)
+		if err := flags.SetValues(f, []string{value}); err != nil {
+			t.Fatalf("%s: SetValues: %v", f.Type(), err)
+		}
+		if got := f.Value(); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: SetValues got %v, Set got %v", f.Type(), got, want)
+		}
+	}
+	for _, f := range []flags.Flag{flags.IntFlag("port", 0, ""), flags.CountFlag("v", 0, ""), flags.AliasFlag("p", flags.IntFlag("port", 0, ""))} {
+		if flags.IsSliceFlag(f) {
+			t.Errorf("expected %s not to be a slice flag", f.Type())
+		}
+	}
+	if !flags.IsSliceFlag(alias) {
+		t.Error("expected alias of a slice flag to be a slice flag")
+	}
+}
+
+func TestNegatedDeprecation(t *testing.T) {
+	fs := flags.NewFlagSet().AddFlags(flags.BoolFlag("cache", true, "use the cache").Deprecated("caching is always on"))
+	f := fs.FromName("no-cache")
+	if f == nil {
+		t.Fatal("expected negated flag")
+	}
+	if !flags.IsHidden(f) || flags.Deprecation(f) != "caching is always on" {
+		t.Errorf("expected negated flag to be deprecated, got hidden=%v deprecation=%q", flags.IsHidden(f), flags.Deprecation(f))
+	}
+}
+
+// minimalFlag implements nothing but the Flag interface, as a flag defined outside the package would
+type minimalFlag struct {
+	value string
+	set   bool
+}
+
+func (f *minimalFlag) Type() flags.FlagType                  { return flags.StringFlagType }
+func (f *minimalFlag) Name() string                          { return "minimal" }
+func (f *minimalFlag) Short() rune                           { return 0 }
+func (f *minimalFlag) Usage() string                         { return "a minimal flag" }
+func (f *minimalFlag) IsSet() bool                           { return f.set }
+func (f *minimalFlag) IsRequired() bool                      { return false }
+func (f *minimalFlag) Value() any                            { return f.value }
+func (f *minimalFlag) Sources() []flags.Valuer               { return nil }
+func (f *minimalFlag) AddSources(...flags.Valuer) flags.Flag { return f }
+func (f *minimalFlag) Required() flags.Flag                  { return f }
+func (f *minimalFlag) Hidden() flags.Flag                    { return f }
+func (f *minimalFlag) Deprecated(string) flags.Flag          { return f }
+func (f *minimalFlag) Set(s string) error                    { f.value, f.set = s, true; return nil }
+
+func TestMinimalFlag(t *testing.T) {
+	f := flags.Implicit(&minimalFlag{}, "x").Hidden().Deprecated("gone")
+	fs := flags.NewFlagSet().AddFlags(f)
+	if flags.IsHidden(f) || flags.Deprecation(f) != "" || flags.Negatable(f) || flags.Completer(f) != nil || fs.FromName("no-minimal") != nil {
+		t.Error("expected optional capabilities to be unsupported")
+	}
+	if _, ok := flags.ImplicitValue(f); ok {
+		t.Error("expected no implicit value")
+	}
+	if got, want := fs.Repr(), "  --minimal  a minimal flag"; got != want {
+		t.Errorf("Repr(): got %q, want %q", got, want)
+	}
+}
@@ -35,4 +35,5 @@ const (
 	Uint64SliceFlagType
 	Float32SliceFlagType
 	Float64SliceFlagType
+	CountFlagType
 )
@@ -39,11 +39,12 @@ func _() {
 	_ = x[Uint64SliceFlagType-28]
 	_ = x[Float32SliceFlagType-29]
 	_ = x[Float64SliceFlagType-30]
+	_ = x[CountFlagType-31]
 }
 
-const _FlagType_name = "UnknownFlagTypeStringFlagTypeBoolFlagTypeDurationFlagTypeIntFlagTypeInt8FlagTypeInt16FlagTypeInt32FlagTypeInt64FlagTypeUintFlagTypeUint8FlagTypeUint16FlagTypeUint32FlagTypeUint64FlagTypeFloat32FlagTypeFloat64FlagTypeStringSliceFlagTypeBoolSliceFlagTypeDurationSliceFlagTypeIntSliceFlagTypeInt8SliceFlagTypeInt16SliceFlagTypeInt32SliceFlagTypeInt64SliceFlagTypeUintSliceFlagTypeUint8SliceFlagTypeUint16SliceFlagTypeUint32SliceFlagTypeUint64SliceFlagTypeFloat32SliceFlagTypeFloat64SliceFlagType"
+const _FlagType_name = "UnknownFlagTypeStringFlagTypeBoolFlagTypeDurationFlagTypeIntFlagTypeInt8FlagTypeInt16FlagTypeInt32FlagTypeInt64FlagTypeUintFlagTypeUint8FlagTypeUint16FlagTypeUint32FlagTypeUint64FlagTypeFloat32FlagTypeFloat64FlagTypeStringSliceFlagTypeBoolSliceFlagTypeDurationSliceFlagTypeIntSliceFlagTypeInt8SliceFlagTypeInt16SliceFlagTypeInt32SliceFlagTypeInt64SliceFlagTypeUintSliceFlagTypeUint8SliceFlagTypeUint16SliceFlagTypeUint32SliceFlagTypeUint64SliceFlagTypeFloat32SliceFlagTypeFloat64SliceFlagTypeCountFlagType"
 
-var _FlagType_index = [...]uint16{0, 15, 29, 41, 57, 68, 80, 93, 106, 119, 131, 144, 158, 172, 186, 201, 216, 235, 252, 273, 289, 306, 324, 342, 360, 377, 395, 414, 433, 452, 472, 492}
+var _FlagType_index = [...]uint16{0, 15, 29, 41, 57, 68, 80, 93, 106, 119, 131, 144, 158, 172, 186, 201, 216, 235, 252, 273, 289, 306, 324, 342, 360, 377, 395, 414, 433, 452, 472, 492, 505}
 
 func (i FlagType) String() string {
 	if i < 0 || i >= FlagType(len(_FlagType_index)-1) {
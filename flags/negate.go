@@ -0,0 +1,93 @@
+package flags
+
+import (
+	"strconv"
+	"strings"
+)
+
+// negationPrefix is prepended to the name of a negatable flag to build its
+// negated form. ie: --cache -> --no-cache
+const negationPrefix = "no-"
+
+// negatable is implemented by flags that can be set to false using --no-<name>
+type negatable interface {
+	negatable() bool
+}
+
+// Negatable reports whether f can be set to false using --no-<name>
+func Negatable(f Flag) bool {
+	n, ok := f.(negatable)
+	return ok && n.negatable()
+}
+
+func (f *boolFlag) negatable() bool { return true }
+
+// negatedFlag wraps a negatable flag, inverting any value set on it.
+// It is returned by FlagSet.FromName when a flag is looked up by its
+// negated name, so that --no-cache sets --cache to false and
+// --no-cache=false sets --cache to true.
+type negatedFlag struct {
+	Flag
+}
+
+func (f negatedFlag) Name() string { return negationPrefix + f.Flag.Name() }
+func (f negatedFlag) Short() rune  { return 0 }
+
+func (f negatedFlag) isHidden() bool      { return IsHidden(f.Flag) }
+func (f negatedFlag) deprecation() string { return Deprecation(f.Flag) }
+
+func (f negatedFlag) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	return f.Flag.Set(strconv.FormatBool(!v))
+}
+
+// helpFlag is the flag added by WithHelpFlag. --no-help makes no sense,
+// so it opts out of negation.
+type helpFlag struct {
+	Flag
+}
+
+func (f helpFlag) negatable() bool { return false }
+
+// fromNegatedName returns the negated form of the flag named by name,
+// if name is the negated form of a negatable flag in the set.
+func (fs *FlagSet) fromNegatedName(name string) Flag {
+	name, found := strings.CutPrefix(name, negationPrefix)
+	if !found {
+		return nil
+	}
+	f, ok := fs.flags[name]
+	if !ok || !Negatable(f) {
+		return nil
+	}
+	return negatedFlag{Flag: f}
+}
+
+func (fs *FlagSet) optionalBoolVal(name string) (*bool, error) {
+	f, err := fs.flag(name, BoolFlagType)
+	if err != nil {
+		return nil, err
+	}
+	if !f.IsSet() {
+		return nil, nil
+	}
+	v := f.Value().(bool)
+	return &v, nil
+}
+
+// LookupOptionalBool returns the value of the bool flag with the given name,
+// or nil if the flag was not set by the command line or any of its sources.
+// This allows distinguishing an explicit --no-cache or --cache=false from a
+// flag that simply defaulted to false.
+func (g FlagGetter) LookupOptionalBool(name string) (*bool, error) {
+	return g.fs.optionalBoolVal(name)
+}
+
+// OptionalBool is like LookupOptionalBool, but ignores any errors
+func (g FlagGetter) OptionalBool(name string) *bool {
+	v, _ := g.LookupOptionalBool(name)
+	return v
+}
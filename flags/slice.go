@@ -0,0 +1,122 @@
+package flags
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// valuesSetter is implemented by slice flags
+type valuesSetter interface {
+	setValues(values []string) error
+}
+
+// SetValues sets the value of a slice flag to values, parsing each as one element of the slice,
+// where Set splits a single value on the slice separator. It returns an error if f is not a slice flag.
+func SetValues(f Flag, values []string) error {
+	s, ok := f.(valuesSetter)
+	if !ok {
+		return fmt.Errorf("gommand: flag --%s is not a slice flag", f.Name())
+	}
+	return s.setValues(values)
+}
+
+// IsSliceFlag reports whether f holds a slice of values, and so accepts SetValues
+func IsSliceFlag(f Flag) bool {
+	if a, ok := f.(*aliasFlag); ok {
+		return IsSliceFlag(a.target)
+	}
+	_, ok := f.(valuesSetter)
+	return ok
+}
+
+// setSlice parses each of values with parse, and only updates the flag if all of them are valid
+func setSlice[T any](f *baseFlag, dst *[]T, values []string, parse func(string) (T, error)) error {
+	v := make([]T, len(values))
+	for i, s := range values {
+		val, err := parse(s)
+		if err != nil {
+			return err
+		}
+		v[i] = val
+	}
+	*dst = v
+	f.set = true
+	return nil
+}
+
+// parseInt returns a parse function for setSlice, parsing integers of the given bit size
+// the same way the generated Set methods do
+func parseInt[T int | int8 | int16 | int32 | int64 | uint | uint8 | uint16 | uint32 | uint64](bitSize int) func(string) (T, error) {
+	return func(s string) (T, error) {
+		v, err := strconv.ParseInt(s, 0, bitSize)
+		return T(v), err
+	}
+}
+
+func parseFloat[T float32 | float64](bitSize int) func(string) (T, error) {
+	return func(s string) (T, error) {
+		v, err := strconv.ParseFloat(s, bitSize)
+		return T(v), err
+	}
+}
+
+func (f *stringSliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, func(s string) (string, error) { return s, nil })
+}
+
+func (f *boolSliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, strconv.ParseBool)
+}
+
+func (f *durationSliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, time.ParseDuration)
+}
+
+func (f *float32SliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, parseFloat[float32](32))
+}
+
+func (f *float64SliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, parseFloat[float64](64))
+}
+
+func (f *intSliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, parseInt[int](64))
+}
+
+func (f *int8SliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, parseInt[int8](8))
+}
+
+func (f *int16SliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, parseInt[int16](16))
+}
+
+func (f *int32SliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, parseInt[int32](32))
+}
+
+func (f *int64SliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, parseInt[int64](64))
+}
+
+func (f *uintSliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, parseInt[uint](64))
+}
+
+func (f *uint8SliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, parseInt[uint8](8))
+}
+
+func (f *uint16SliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, parseInt[uint16](16))
+}
+
+func (f *uint32SliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, parseInt[uint32](32))
+}
+
+func (f *uint64SliceFlag) setValues(values []string) error {
+	return setSlice(f.baseFlag, &f.value, values, parseInt[uint64](64))
+}
@@ -23,5 +23,11 @@ var (
 type environ struct{}
 
 func (e *environ) Value(name string) (string, bool) {
-	return os.LookupEnv(strman.ToScreamingSnake(name))
+	return os.LookupEnv(EnvName(name))
+}
+
+// EnvName returns the name of the environment variable Environ reads the value of the
+// flag called name from. ie: log-level is read from LOG_LEVEL
+func EnvName(name string) string {
+	return strman.ToScreamingSnake(name)
 }
@@ -1,6 +1,7 @@
 package gommand_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -78,6 +79,35 @@ func TestFlagParsing(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:  "negated bool flag sets false",
+			args:  []string{"--no-verbose"},
+			flags: []flags.Flag{flags.BoolFlag("verbose", true, "verbose output")},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.OptionalBool("verbose"); got == nil || *got {
+					t.Errorf("got %v, want explicit false", got)
+				}
+			},
+		},
+		{
+			name: "negated bool flag overrides source",
+			args: []string{"--no-verbose"},
+			flags: []flags.Flag{
+				flags.BoolFlag("verbose", false, "verbose output").
+					AddSources(flags.ValuerFunc(func(string) (string, bool) { return "true", true })),
+			},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.Bool("verbose"); got {
+					t.Errorf("got %v, want false", got)
+				}
+			},
+		},
+		{
+			name:    "negated non-bool flag is error",
+			args:    []string{"--no-name"},
+			flags:   []flags.Flag{flags.StringFlag("name", "", "name")},
+			wantErr: true,
+		},
 		{
 			name: "multi-flag sets all bool flags",
 			args: []string{"-abc"},
@@ -95,14 +125,192 @@ func TestFlagParsing(t *testing.T) {
 			},
 		},
 		{
-			name: "multi-flag with non-bool flag is error",
-			args: []string{"-as"},
+			name: "multi-flag with unknown flag is error",
+			args: []string{"-az"},
+			flags: []flags.Flag{
+				flags.BoolFlagS("alpha", 'a', false, "alpha"),
+			},
+			wantErr: true,
+		},
+		{
+			name:  "short flag with attached value",
+			args:  []string{"-n5"},
+			flags: []flags.Flag{flags.IntFlagS("num", 'n', 0, "num")},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.Int("num"); got != 5 {
+					t.Errorf("got %d, want 5", got)
+				}
+			},
+		},
+		{
+			name: "multi-flag non-bool flag consumes next token",
+			args: []string{"-abs", "out", "arg"},
 			flags: []flags.Flag{
 				flags.BoolFlagS("alpha", 'a', false, "alpha"),
+				flags.BoolFlagS("bravo", 'b', false, "bravo"),
 				flags.StringFlagS("str", 's', "", "str"),
 			},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if !fg.Bool("alpha") || !fg.Bool("bravo") {
+					t.Error("expected alpha and bravo to be true")
+				}
+				if got := fg.String("str"); got != "out" {
+					t.Errorf("got %q, want %q", got, "out")
+				}
+			},
+		},
+		{
+			name: "multi-flag non-bool flag consumes rest of cluster",
+			args: []string{"-asab"},
+			flags: []flags.Flag{
+				flags.BoolFlagS("alpha", 'a', false, "alpha"),
+				flags.BoolFlagS("bravo", 'b', false, "bravo"),
+				flags.StringFlagS("str", 's', "", "str"),
+			},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.String("str"); got != "ab" {
+					t.Errorf("got %q, want %q", got, "ab")
+				}
+				if fg.Bool("bravo") {
+					t.Error("expected bravo to be consumed as part of the value")
+				}
+			},
+		},
+		{
+			name: "multi-flag non-bool flag with = value",
+			args: []string{"-as=file"},
+			flags: []flags.Flag{
+				flags.BoolFlagS("alpha", 'a', false, "alpha"),
+				flags.StringFlagS("str", 's', "", "str"),
+			},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.String("str"); got != "file" {
+					t.Errorf("got %q, want %q", got, "file")
+				}
+			},
+		},
+		{
+			name:  "short flag with attached value containing =",
+			args:  []string{"-skey=value"},
+			flags: []flags.Flag{flags.StringFlagS("str", 's', "", "str")},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.String("str"); got != "key=value" {
+					t.Errorf("got %q, want %q", got, "key=value")
+				}
+			},
+		},
+		{
+			name:  "count flag counts clustered occurrences",
+			args:  []string{"-vvv"},
+			flags: []flags.Flag{flags.CountFlagS("verbose", 'v', 0, "verbose")},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.Count("verbose"); got != 3 {
+					t.Errorf("got %d, want 3", got)
+				}
+			},
+		},
+		{
+			name:  "count flag counts repeated occurrences",
+			args:  []string{"--verbose", "-v", "--verbose", "arg"},
+			flags: []flags.Flag{flags.CountFlagS("verbose", 'v', 0, "verbose")},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.Count("verbose"); got != 3 {
+					t.Errorf("got %d, want 3", got)
+				}
+			},
+		},
+		{
+			name: "count flag in cluster with value flag",
+			args: []string{"-vvo", "out"},
+			flags: []flags.Flag{
+				flags.CountFlagS("verbose", 'v', 0, "verbose"),
+				flags.StringFlagS("output", 'o', "", "output"),
+			},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.Count("verbose"); got != 2 {
+					t.Errorf("got %d, want 2", got)
+				}
+				if got := fg.String("output"); got != "out" {
+					t.Errorf("got %q, want %q", got, "out")
+				}
+			},
+		},
+		{
+			name:    "long flag without value at end",
+			args:    []string{"--out"},
+			flags:   []flags.Flag{flags.StringFlagS("out", 'o', "default", "out")},
 			wantErr: true,
 		},
+		{
+			name:    "short flag without value at end",
+			args:    []string{"-o"},
+			flags:   []flags.Flag{flags.StringFlagS("out", 'o', "default", "out")},
+			wantErr: true,
+		},
+		{
+			name:    "flag followed by a flag instead of a value",
+			args:    []string{"--out", "--verbose"},
+			flags:   []flags.Flag{flags.StringFlagS("out", 'o', "default", "out"), flags.BoolFlag("verbose", false, "verbose")},
+			wantErr: true,
+		},
+		{
+			name:  "count flag explicit value",
+			args:  []string{"--verbose=5"},
+			flags: []flags.Flag{flags.CountFlagS("verbose", 'v', 0, "verbose")},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.Count("verbose"); got != 5 {
+					t.Errorf("got %d, want 5", got)
+				}
+			},
+		},
+		{
+			name:  "implicit value flag bare does not consume next token",
+			args:  []string{"--color", "file.txt"},
+			flags: []flags.Flag{flags.Implicit(flags.StringFlag("color", "never", "color"), "always")},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.String("color"); got != "always" {
+					t.Errorf("got %q, want %q", got, "always")
+				}
+			},
+		},
+		{
+			name:  "implicit value flag with = value",
+			args:  []string{"--color=auto"},
+			flags: []flags.Flag{flags.Implicit(flags.StringFlag("color", "never", "color"), "always")},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.String("color"); got != "auto" {
+					t.Errorf("got %q, want %q", got, "auto")
+				}
+			},
+		},
+		{
+			name:  "implicit value flag not provided uses default",
+			args:  []string{},
+			flags: []flags.Flag{flags.Implicit(flags.StringFlag("color", "never", "color"), "always")},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.String("color"); got != "never" {
+					t.Errorf("got %q, want %q", got, "never")
+				}
+			},
+		},
+		{
+			name: "implicit value short flag in cluster",
+			args: []string{"-ac", "file.txt"},
+			flags: []flags.Flag{
+				flags.BoolFlagS("alpha", 'a', false, "alpha"),
+				flags.Implicit(flags.StringFlagS("color", 'c', "never", "color"), "always"),
+			},
+			check: func(t *testing.T, fg *flags.FlagGetter) {
+				if got := fg.String("color"); got != "always" {
+					t.Errorf("got %q, want %q", got, "always")
+				}
+			},
+		},
+		{
+			name:  "lone dash is an argument",
+			args:  []string{"-"},
+			flags: []flags.Flag{flags.BoolFlagS("alpha", 'a', false, "alpha")},
+		},
 		{
 			name: "multi-flag with = value is error",
 			args: []string{"-ab=val"},
@@ -192,6 +400,27 @@ func TestFlagParsing(t *testing.T) {
 	}
 }
 
+func TestCountFlagExecutions(t *testing.T) {
+	var got int
+	cmd := &gommand.Command{
+		Name:    "test",
+		FlagSet: flags.NewFlagSet().AddFlags(flags.CountFlagS("verbose", 'v', 0, "verbose")),
+		Run: func(ctx *gommand.Context) error {
+			got = ctx.Flags().Count("verbose")
+			return nil
+		},
+	}
+
+	for i := range 2 {
+		if err := cmd.ExecuteArgs(context.Background(), []string{"-vv"}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got != 2 {
+			t.Errorf("execution %d: got %d, want 2", i+1, got)
+		}
+	}
+}
+
 func TestRequiredFlags(t *testing.T) {
 	t.Run("required flag missing returns ErrMissingRequiredFlag", func(t *testing.T) {
 		defer overwriteArgs([]string{})()
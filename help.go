@@ -0,0 +1,295 @@
+package gommand
+
+import (
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/jimmykodes/gommand/flags"
+	"github.com/jimmykodes/gommand/internal/suggest"
+)
+
+// DefaultHelpTemplate is the template used to render help text for commands that do not
+// define a HelpTemplate, and have no parent that does. It is rendered with HelpData.
+//
+// It is exported so custom templates can be built on top of it.
+// ie: HelpTemplate: gommand.DefaultHelpTemplate + "\nLearn more:\n  https://example.com/docs\n"
+const DefaultHelpTemplate = `{{with or .Description .Usage}}{{.}}
+
+{{end}}Usage:
+  {{.UsageLine}}{{if .Commands}} [commands]{{end}}
+
+{{if .Aliases}}Aliases:
+  {{join .Aliases ", "}}
+
+{{end}}{{if .Args}}Arguments:
+{{range .Args}}  {{rpad .Synopsis $.ArgWidth}}  {{.Flag.Usage}}
+{{end}}
+{{end}}{{range .CommandGroups}}{{.Title}}:
+{{range .Commands}}  {{rpad .Name $.CommandWidth}}  {{.Usage}}
+{{end}}
+{{end}}{{if .UserAliases}}User Aliases:
+{{range .UserAliases}}  {{.Name}} = {{.Expansion}}
+{{end}}
+{{end}}{{if .Plugins}}Plugins:
+{{range .Plugins}}  {{.}}
+{{end}}
+{{end}}Flags:
+{{flagUsages .Flags}}
+{{with flagUsages .InheritedFlags}}
+Global Flags:
+{{.}}
+{{end}}`
+
+// HelpData is the data a HelpTemplate is executed with.
+//
+// Along with the builtin text/template functions, templates can use:
+//   - join: strings.Join
+//   - rpad: pads a string with spaces on the right to the given length
+//   - flagUsages: renders a slice of flags as aligned usage lines, as they appear in the default help text
+type HelpData struct {
+	// Command is the command the help text is for
+	Command *Command
+
+	// Name is the name of the command. ie: run
+	Name string
+
+	// Path is the names of the commands from the root of the tree to the command.
+	// ie: mytool server run
+	Path string
+
+	// UsageLine is the path of the command followed by any usage descriptions from the
+	// command's Name. ie: mytool server run [--port port]
+	UsageLine string
+
+	// Usage is the command's Usage
+	Usage string
+
+	// Description is the command's Description
+	Description string
+
+	// Aliases are the command's Aliases
+	Aliases []string
+
+	// Args are the named positional args of the command, see Command.Args
+	Args []Arg
+
+	// ArgWidth is the length of the longest synopsis in Args, for aligning them
+	ArgWidth int
+
+	// Commands are the subcommands that are not hidden, in the order they are listed
+	Commands []HelpCommand
+
+	// CommandGroups are the sections Commands are listed under. Any Commands that do not belong
+	// to one of the command's Groups are listed last, under "Additional Commands" if the command
+	// defines any Groups, or "Available Commands" otherwise
+	CommandGroups []HelpGroup
+
+	// UserAliases are the user defined aliases of the command, sorted by name, see Command.UserAliases.
+	// They are only listed in the help text printed when executing the command, as they depend
+	// on the machine it runs on, and are not returned by HelpData
+	UserAliases []HelpAlias
+
+	// Plugins are the names of the plugins found for the command, see Command.Plugins. Like
+	// UserAliases, they are only listed in the help text printed when executing the command
+	Plugins []string
+
+	// CommandWidth is the length of the longest name in Commands, for aligning them
+	CommandWidth int
+
+	// Flags are the command's own flags, including --help, sorted by name. Hidden flags are not included
+	Flags []flags.Flag
+
+	// InheritedFlags are the persistent flags of the command and its parents, sorted by name.
+	// Hidden flags are not included
+	InheritedFlags []flags.Flag
+
+	// Version is the version of the command, as printed by --version, or an empty string if
+	// no version is set
+	Version string
+}
+
+// HelpCommand describes a subcommand in HelpData
+type HelpCommand struct {
+	Name    string
+	Usage   string
+	Aliases []string
+	Command *Command
+}
+
+// HelpAlias describes a user defined alias in HelpData
+type HelpAlias struct {
+	Name      string
+	Expansion string
+}
+
+// HelpGroup is a titled section of subcommands in HelpData
+type HelpGroup struct {
+	Title    string
+	Commands []HelpCommand
+}
+
+var helpFuncs = template.FuncMap{
+	"join": strings.Join,
+	"rpad": func(s string, n int) string {
+		return s + strings.Repeat(" ", max(n-len(s), 0))
+	},
+	"flagUsages": flags.Usages,
+}
+
+// writeHelp renders the command's help template to w, listing the user aliases and plugins
+// found when executing it along with the HelpData
+func (c *Command) writeHelp(ctx *Context, w io.Writer) error {
+	tmpl, err := template.New(c.name()).Funcs(helpFuncs).Parse(c.helpTemplate())
+	if err != nil {
+		return err
+	}
+
+	data := c.HelpData()
+	if c.parent == nil {
+		data.Plugins = ctx.scanPlugins(c)
+		for _, name := range slices.Sorted(maps.Keys(ctx.userAliases)) {
+			data.UserAliases = append(data.UserAliases, HelpAlias{Name: name, Expansion: ctx.userAliases[name]})
+		}
+	}
+	return tmpl.Execute(w, data)
+}
+
+// helpTemplate returns the HelpTemplate of the command, or the closest of its parents that
+// defines one, falling back to DefaultHelpTemplate
+func (c *Command) helpTemplate() string {
+	for p := c; p != nil; p = p.parent {
+		if p.HelpTemplate != "" {
+			return p.HelpTemplate
+		}
+	}
+	return DefaultHelpTemplate
+}
+
+// HelpData returns the data the command's help text is rendered with, apart from the
+// UserAliases and Plugins, which are only found when executing the command. It does not read
+// the filesystem, so documentation generated from it does not depend on the machine it runs on.
+func (c *Command) HelpData() HelpData {
+	usageLine := c.path()
+	if usage := c.usage(); usage != "" {
+		usageLine += " " + usage
+	}
+
+	fs := flags.NewFlagSet(flags.WithHelpFlag()).AddFlagSet(c.FlagSet)
+	pfs := flags.NewFlagSet()
+	for p := c; p != nil; p = p.parent {
+		pfs.AddFlagSet(p.PersistentFlagSet)
+	}
+
+	data := HelpData{
+		Command:        c,
+		Name:           c.name(),
+		Path:           c.path(),
+		UsageLine:      usageLine,
+		Usage:          c.Usage,
+		Description:    c.Description,
+		Aliases:        c.Aliases,
+		Args:           c.Args,
+		Flags:          visibleFlags(fs),
+		InheritedFlags: visibleFlags(pfs),
+		Version:        c._version(),
+	}
+
+	for _, arg := range c.Args {
+		data.ArgWidth = max(data.ArgWidth, len(arg.Synopsis()))
+	}
+
+	visible := c.visibleCommands()
+	grouped := make(map[string][]HelpCommand)
+	for _, cmd := range visible {
+		hc := HelpCommand{Name: cmd.name(), Usage: cmd.Usage, Aliases: cmd.Aliases, Command: cmd}
+		data.Commands = append(data.Commands, hc)
+		data.CommandWidth = max(data.CommandWidth, len(hc.Name))
+		grouped[cmd.Group] = append(grouped[cmd.Group], hc)
+	}
+	for _, group := range c.Groups {
+		if cmds := grouped[group.ID]; len(cmds) > 0 {
+			data.CommandGroups = append(data.CommandGroups, HelpGroup{Title: group.Title, Commands: cmds})
+		}
+		delete(grouped, group.ID)
+	}
+
+	rest := HelpGroup{Title: "Available Commands"}
+	if len(c.Groups) > 0 {
+		rest.Title = "Additional Commands"
+	}
+	for _, cmd := range visible {
+		if _, ok := grouped[cmd.Group]; ok {
+			rest.Commands = append(rest.Commands, HelpCommand{Name: cmd.name(), Usage: cmd.Usage, Aliases: cmd.Aliases, Command: cmd})
+		}
+	}
+	if len(rest.Commands) > 0 {
+		data.CommandGroups = append(data.CommandGroups, rest)
+	}
+
+	return data
+}
+
+// newHelpCommand returns the command registered by WithHelpCommand
+func newHelpCommand() *Command {
+	return &Command{
+		Name:         "help [command...]",
+		Usage:        "show help for a command",
+		ArgValidator: ArgsAny(),
+		Run: func(ctx *Context) error {
+			cmd := ctx.cmd.parent
+			for i, name := range ctx.Args() {
+				loc := Location{CommandPath: cmd.path(), Position: ctx.argPositions[i]}
+				next, err := cmd.commands.lookup(name, ctx.prefixMatching)
+				if err != nil {
+					var ambiguous ErrAmbiguousCommand
+					if errors.As(err, &ambiguous) {
+						ambiguous.Location = loc
+						return ambiguous
+					}
+					return err
+				}
+				if next == nil {
+					return ErrUnknownCommand{
+						Location:    loc,
+						Name:        name,
+						Suggestions: suggest.Closest(name, slices.Collect(maps.Keys(cmd.commands.visible()))),
+					}
+				}
+				cmd = next
+			}
+			return cmd.writeHelp(ctx, ctx.Stdout())
+		},
+	}
+}
+
+// visibleCommands returns the subcommands listed in the help text, sorted by name
+// unless DeclarationOrder is set.
+func (c *Command) visibleCommands() []*Command {
+	var out []*Command
+	for _, cmd := range c.children {
+		if cmd.Hidden || cmd.Deprecated != "" || c.commands[cmd.name()] != cmd {
+			continue
+		}
+		out = append(out, cmd)
+	}
+	if !c.DeclarationOrder {
+		slices.SortStableFunc(out, func(a, b *Command) int { return strings.Compare(a.name(), b.name()) })
+	}
+	return out
+}
+
+// visibleFlags returns the flags in fs that are not hidden, sorted by name
+func visibleFlags(fs *flags.FlagSet) []flags.Flag {
+	var out []flags.Flag
+	for _, f := range flags.NewFlagGetter(fs).All() {
+		if !flags.IsHidden(f) {
+			out = append(out, f)
+		}
+	}
+	slices.SortFunc(out, func(a, b flags.Flag) int { return strings.Compare(a.Name(), b.Name()) })
+	return out
+}
@@ -0,0 +1,245 @@
+package gommand_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
+)
+
+func TestHelpTemplate(t *testing.T) {
+	tmpl := `{{.Path}} {{.Version}}
+{{range .Commands}}{{.Name}}:{{.Usage}} {{end}}
+{{range .Flags}}{{.Name}} {{end}}
+{{range .InheritedFlags}}{{.Name}} {{end}}
+`
+
+	t.Run("root template", func(t *testing.T) {
+		root := &gommand.Command{
+			Name:    "mytool",
+			Version: "1.2.3",
+			PersistentFlagSet: flags.NewFlagSet().AddFlags(
+				flags.BoolFlagS("verbose", 'v', false, "verbose output"),
+				flags.BoolFlag("trace", false, "").Hidden(),
+			),
+			HelpTemplate: tmpl,
+		}
+		root.SubCommand(&gommand.Command{Name: "server", Usage: "manage servers", Run: func(*gommand.Context) error { return nil }})
+
+		var buf bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"--help"}, gommand.WithStdout(&buf)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := buf.String(), "mytool 1.2.3\nserver:manage servers \nhelp \nverbose \n"; got != want {
+			t.Errorf("got:\n%q\nwant:\n%q", got, want)
+		}
+	})
+
+	t.Run("inherited by subcommands", func(t *testing.T) {
+		root := &gommand.Command{
+			Name:              "mytool",
+			Version:           "1.2.3",
+			PersistentFlagSet: flags.NewFlagSet().AddFlags(flags.BoolFlagS("verbose", 'v', false, "verbose output")),
+			HelpTemplate:      tmpl,
+		}
+		server := &gommand.Command{Name: "server"}
+		server.SubCommand(&gommand.Command{
+			Name:    "run [--port port]",
+			FlagSet: flags.NewFlagSet().AddFlags(flags.IntFlag("port", 8080, "port to listen on")),
+			Run:     func(*gommand.Context) error { return nil },
+		})
+		root.SubCommand(server)
+
+		var buf bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"server", "run", "--help"}, gommand.WithStdout(&buf)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := buf.String(), "mytool server run 1.2.3\n\nhelp port \nverbose \n"; got != want {
+			t.Errorf("got:\n%q\nwant:\n%q", got, want)
+		}
+	})
+
+	t.Run("sibling override is not inherited", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", Version: "1.2.3", HelpTemplate: tmpl}
+		root.SubCommand(
+			&gommand.Command{Name: "run", Run: func(*gommand.Context) error { return nil }},
+			&gommand.Command{Name: "status", HelpTemplate: "{{.UsageLine}}: {{.Usage}}\n", Run: func(*gommand.Context) error { return nil }},
+		)
+
+		var buf bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"run", "--help"}, gommand.WithStdout(&buf)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := buf.String(), "mytool run 1.2.3\n\nhelp \n\n"; got != want {
+			t.Errorf("got:\n%q\nwant:\n%q", got, want)
+		}
+	})
+
+	t.Run("overridden by subcommand", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", HelpTemplate: tmpl}
+		root.SubCommand(&gommand.Command{
+			Name:         "status [-w]",
+			HelpTemplate: "{{.UsageLine}}: {{.Usage}}\n",
+			Usage:        "show status",
+			Run:          func(*gommand.Context) error { return nil },
+		})
+
+		var buf bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"status", "--help"}, gommand.WithStdout(&buf)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := buf.String(), "mytool status [-w]: show status\n"; got != want {
+			t.Errorf("got:\n%q\nwant:\n%q", got, want)
+		}
+	})
+
+	t.Run("default template functions", func(t *testing.T) {
+		root := &gommand.Command{
+			Name: "mytool",
+			PersistentFlagSet: flags.NewFlagSet().AddFlags(
+				flags.BoolFlagS("verbose", 'v', false, "verbose output"),
+				flags.BoolFlag("trace", false, "").Hidden(),
+			),
+			HelpTemplate: `{{range .CommandGroups}}{{.Title}}:{{range .Commands}} [{{rpad .Name 5}}] {{join .Aliases ","}}{{end}}{{end}}
+{{flagUsages .InheritedFlags}}
+`,
+		}
+		server := &gommand.Command{Name: "server"}
+		server.SubCommand(
+			&gommand.Command{Name: "run", Aliases: []string{"start"}, Run: func(*gommand.Context) error { return nil }},
+			&gommand.Command{Name: "debug", Hidden: true, Run: func(*gommand.Context) error { return nil }},
+		)
+		root.SubCommand(server)
+
+		var buf bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"server", "--help"}, gommand.WithStdout(&buf)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got, want := buf.String(), "Available Commands: [run  ] start\n  -v, --[no-]verbose  verbose output\n"; got != want {
+			t.Errorf("got:\n%q\nwant:\n%q", got, want)
+		}
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		root := &gommand.Command{Name: "root", HelpTemplate: "{{.Missing}}"}
+		var buf bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"--help"}, gommand.WithStdout(&buf)); err == nil {
+			t.Error("expected template error")
+		}
+	})
+}
+
+func TestHelpCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		helpArgs []string
+	}{
+		{name: "root", args: []string{"help"}, helpArgs: []string{"--help"}},
+		{name: "subcommand", args: []string{"help", "server"}, helpArgs: []string{"server", "--help"}},
+		{name: "nested", args: []string{"help", "server", "run"}, helpArgs: []string{"server", "run", "--help"}},
+		{name: "alias", args: []string{"help", "srv", "stop"}, helpArgs: []string{"server", "stop", "--help"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &gommand.Command{Name: "mytool", Usage: "my tool"}
+			server := &gommand.Command{Name: "server", Aliases: []string{"srv"}, Usage: "manage servers"}
+			server.SubCommand(
+				&gommand.Command{Name: "run", Usage: "run a server", Run: func(*gommand.Context) error { return nil }},
+				&gommand.Command{Name: "stop", Usage: "stop a server", Run: func(*gommand.Context) error { return nil }},
+			)
+			root.SubCommand(server)
+
+			var want bytes.Buffer
+			if err := root.ExecuteArgs(context.Background(), tt.helpArgs, gommand.WithStdout(&want), gommand.WithHelpCommand()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var buf bytes.Buffer
+			if err := root.ExecuteArgs(context.Background(), tt.args, gommand.WithStdout(&buf), gommand.WithHelpCommand()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := buf.String(); got != want.String() {
+				t.Errorf("got:\n%s\nwant:\n%s", got, want.String())
+			}
+		})
+	}
+
+	t.Run("listed in help", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool"}
+		root.SubCommand(&gommand.Command{Name: "server", Run: func(*gommand.Context) error { return nil }})
+
+		var buf bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"--help"}, gommand.WithStdout(&buf), gommand.WithHelpCommand()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(buf.String(), "  help    show help for a command\n") {
+			t.Errorf("help command missing from help text:\n%s", buf.String())
+		}
+	})
+
+	t.Run("unknown command", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", SilenceHelp: true, SilenceError: true}
+		server := &gommand.Command{Name: "server"}
+		server.SubCommand(&gommand.Command{Name: "run", Run: func(*gommand.Context) error { return nil }})
+		root.SubCommand(server)
+
+		err := root.ExecuteArgs(context.Background(), []string{"help", "server", "rnu"}, gommand.WithHelpCommand())
+		var target gommand.ErrUnknownCommand
+		if !errors.As(err, &target) {
+			t.Fatalf("expected ErrUnknownCommand, got %T: %v", err, err)
+		}
+		want := gommand.ErrUnknownCommand{
+			Location:    gommand.Location{CommandPath: "mytool server", Position: 2},
+			Name:        "rnu",
+			Suggestions: []string{"run"},
+		}
+		if !reflect.DeepEqual(target, want) {
+			t.Errorf("got %#v, want %#v", target, want)
+		}
+	})
+
+	t.Run("not registered by default", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{Name: "server", Run: func(*gommand.Context) error { return nil }})
+
+		if err := root.ExecuteArgs(context.Background(), []string{"help", "server"}); err == nil {
+			t.Error("expected error without WithHelpCommand")
+		}
+	})
+
+	t.Run("not left registered", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{Name: "server", Run: func(*gommand.Context) error { return nil }})
+
+		if err := root.ExecuteArgs(context.Background(), []string{"help"}, gommand.WithStdout(io.Discard), gommand.WithHelpCommand()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, sub := range root.HelpData().Commands {
+			if sub.Name == "help" {
+				t.Error("expected help command to be unregistered after execution")
+			}
+		}
+		if err := root.ExecuteArgs(context.Background(), []string{"help"}); err == nil {
+			t.Error("expected error executing without WithHelpCommand")
+		}
+	})
+
+	t.Run("existing help command is kept", func(t *testing.T) {
+		var ran bool
+		root := &gommand.Command{Name: "mytool"}
+		root.SubCommand(&gommand.Command{Name: "help", Run: func(*gommand.Context) error { ran = true; return nil }})
+
+		if err := root.ExecuteArgs(context.Background(), []string{"help"}, gommand.WithHelpCommand()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ran {
+			t.Error("expected user defined help command to run")
+		}
+	})
+}
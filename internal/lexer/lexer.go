@@ -14,6 +14,7 @@ const (
 	ShortFlagType
 	MultiFlagType
 	ValueType
+	TerminatorType
 )
 
 func (t TokenType) String() string {
@@ -23,6 +24,7 @@ func (t TokenType) String() string {
 		"ShortFlagType",
 		"MultiFlagType",
 		"ValueType",
+		"TerminatorType",
 	}[t]
 }
 
@@ -35,20 +37,46 @@ type Token struct {
 type Lexer struct {
 	strs []string
 	pos  int
+
+	// terminated is true once a `--` token has been read. Every token
+	// after the terminator is returned as a ValueType, regardless of its
+	// contents.
+	terminated bool
 }
 
 func (l *Lexer) Read() *Token {
 	t := l.Peek()
 	if t != nil {
 		l.pos++
+		if t.Type == TerminatorType {
+			l.terminated = true
+		}
 	}
 	return t
 }
 
+// Last returns the index and raw value of the most recent argument returned by Read,
+// or -1 and an empty string if nothing has been read.
+func (l *Lexer) Last() (int, string) {
+	if l.pos == 0 {
+		return -1, ""
+	}
+	return l.pos - 1, l.strs[l.pos-1]
+}
+
 func (l *Lexer) Peek() *Token {
 	if l.pos >= len(l.strs) {
 		return nil
 	}
+	if l.terminated {
+		return &Token{
+			Type:  ValueType,
+			Value: l.strs[l.pos],
+		}
+	}
+	if l.strs[l.pos] == "--" {
+		return &Token{Type: TerminatorType}
+	}
 	flag, value, _ := strings.Cut(l.strs[l.pos], "=")
 	if name, found := strings.CutPrefix(flag, "--"); found {
 		return &Token{
@@ -57,7 +85,7 @@ func (l *Lexer) Peek() *Token {
 			Value: value,
 		}
 	}
-	if name, found := strings.CutPrefix(flag, "-"); found {
+	if name, found := strings.CutPrefix(flag, "-"); found && name != "" {
 		if char := name[0]; '0' <= char && char <= '9' {
 			// flag is -[numeric][chars...] so assume this is a
 			// negative number, not a flag, and return it as a value.
@@ -85,3 +113,11 @@ func (l *Lexer) Peek() *Token {
 		Value: l.strs[l.pos],
 	}
 }
+
+// Rest returns the raw arguments that have not been read yet, as they were passed, and
+// consumes them.
+func (l *Lexer) Rest() []string {
+	rest := l.strs[l.pos:]
+	l.pos = len(l.strs)
+	return rest
+}
@@ -17,6 +17,7 @@ func TestLexer(t *testing.T) {
 		"-iec=false",
 		"--idx", "-1",
 		"-c", "-100",
+		"-",
 	})
 	expected := []*lexer.Token{
 		{
@@ -66,6 +67,51 @@ func TestLexer(t *testing.T) {
 			Type:  lexer.ValueType,
 			Value: "-100",
 		},
+		{
+			Type:  lexer.ValueType,
+			Value: "-",
+		},
+	}
+
+	for _, e := range expected {
+		if token := l.Read(); !reflect.DeepEqual(token, e) {
+			t.Errorf("invalid token. got %+v - want %+v", token, e)
+		}
+	}
+}
+
+func TestLexerTerminator(t *testing.T) {
+	l := lexer.New([]string{
+		"-f",
+		"--",
+		"--port", "8080",
+		"-lvg",
+		"--",
+	})
+	expected := []*lexer.Token{
+		{
+			Type: lexer.ShortFlagType,
+			Name: "f",
+		},
+		{
+			Type: lexer.TerminatorType,
+		},
+		{
+			Type:  lexer.ValueType,
+			Value: "--port",
+		},
+		{
+			Type:  lexer.ValueType,
+			Value: "8080",
+		},
+		{
+			Type:  lexer.ValueType,
+			Value: "-lvg",
+		},
+		{
+			Type:  lexer.ValueType,
+			Value: "--",
+		},
 	}
 
 	for _, e := range expected {
@@ -73,4 +119,39 @@ func TestLexer(t *testing.T) {
 			t.Errorf("invalid token. got %+v - want %+v", token, e)
 		}
 	}
+	if token := l.Read(); token != nil {
+		t.Errorf("expected nil token after end of input, got %+v", token)
+	}
+}
+
+func TestLexerLast(t *testing.T) {
+	l := lexer.New([]string{"--port", "8080"})
+	if pos, raw := l.Last(); pos != -1 || raw != "" {
+		t.Errorf("Last() before Read = %d, %q, want -1, \"\"", pos, raw)
+	}
+	l.Read()
+	l.Peek()
+	if pos, raw := l.Last(); pos != 0 || raw != "--port" {
+		t.Errorf("Last() = %d, %q, want 0, %q", pos, raw, "--port")
+	}
+	l.Read()
+	if pos, raw := l.Last(); pos != 1 || raw != "8080" {
+		t.Errorf("Last() = %d, %q, want 1, %q", pos, raw, "8080")
+	}
+}
+
+func TestLexerRest(t *testing.T) {
+	l := lexer.New([]string{"--port", "8080", "plugin", "--help", "-x=1"})
+	l.Read()
+	l.Read()
+	l.Read()
+	if got, want := l.Rest(), []string{"--help", "-x=1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Rest() = %q, want %q", got, want)
+	}
+	if token := l.Read(); token != nil {
+		t.Errorf("expected nil token after Rest, got %+v", token)
+	}
+	if pos, raw := l.Last(); pos != 4 || raw != "-x=1" {
+		t.Errorf("Last() = %d, %q, want 4, %q", pos, raw, "-x=1")
+	}
 }
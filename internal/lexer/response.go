@@ -0,0 +1,80 @@
+package lexer
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jimmykodes/gommand/internal/shellwords"
+)
+
+// ExpandResponseFiles replaces every `@path` argument in args with the shell-word
+// split contents of the file at path. Response files may reference other response
+// files, up to maxDepth levels deep.
+//
+// A literal argument beginning with `@` can be passed by escaping it as `@@`,
+// and arguments after a `--` terminator are never expanded.
+//
+// Along with the expanded arguments, it returns the index in args of the argument
+// each of them came from.
+func ExpandResponseFiles(args []string, maxDepth int) ([]string, []int, error) {
+	out, origins, _, err := expandResponseFiles(args, maxDepth, 0)
+	return out, origins, err
+}
+
+// expandResponseFiles expands args, reporting whether a `--` terminator was
+// encountered, in which case nothing after it has been expanded.
+func expandResponseFiles(args []string, maxDepth, depth int) ([]string, []int, bool, error) {
+	var (
+		out     = make([]string, 0, len(args))
+		origins = make([]int, 0, len(args))
+	)
+	add := func(i int, words ...string) {
+		out = append(out, words...)
+		for range words {
+			origins = append(origins, i)
+		}
+	}
+	rest := func(i int) {
+		for ; i < len(args); i++ {
+			add(i, args[i])
+		}
+	}
+	for i, arg := range args {
+		if arg == "--" {
+			rest(i)
+			return out, origins, true, nil
+		}
+		path, found := strings.CutPrefix(arg, "@")
+		if !found || path == "" {
+			add(i, arg)
+			continue
+		}
+		if strings.HasPrefix(path, "@") {
+			// escaped literal: @@foo -> @foo
+			add(i, path)
+			continue
+		}
+		if depth >= maxDepth {
+			return nil, nil, false, fmt.Errorf("gommand: response file %s: exceeded max depth of %d", path, maxDepth)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("gommand: response file: %w", err)
+		}
+		words, err := shellwords.Split(string(data))
+		if err != nil {
+			return nil, nil, false, fmt.Errorf("gommand: response file %s: %w", path, err)
+		}
+		expanded, _, terminated, err := expandResponseFiles(words, maxDepth, depth+1)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		add(i, expanded...)
+		if terminated {
+			rest(i + 1)
+			return out, origins, true, nil
+		}
+	}
+	return out, origins, false, nil
+}
@@ -0,0 +1,104 @@
+package lexer_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jimmykodes/gommand/internal/lexer"
+)
+
+func TestExpandResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	includes := write("includes.txt", "# includes\n--include a\n--include 'b c'\n")
+	nested := write("nested.txt", "--verbose @"+includes+"\n")
+	terminated := write("terminated.txt", "-- @"+includes)
+	loop := filepath.Join(dir, "loop.txt")
+	write("loop.txt", "@"+loop)
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		origins []int
+		wantErr string
+	}{
+		{
+			name:    "no response files",
+			args:    []string{"run", "--port", "8080"},
+			want:    []string{"run", "--port", "8080"},
+			origins: []int{0, 1, 2},
+		},
+		{
+			name:    "response file",
+			args:    []string{"run", "@" + includes, "arg"},
+			want:    []string{"run", "--include", "a", "--include", "b c", "arg"},
+			origins: []int{0, 1, 1, 1, 1, 2},
+		},
+		{
+			name:    "nested response file",
+			args:    []string{"@" + nested},
+			want:    []string{"--verbose", "--include", "a", "--include", "b c"},
+			origins: []int{0, 0, 0, 0, 0},
+		},
+		{
+			name:    "escaped @",
+			args:    []string{"@@handle", "@"},
+			want:    []string{"@handle", "@"},
+			origins: []int{0, 1},
+		},
+		{
+			name:    "no expansion after terminator",
+			args:    []string{"run", "--", "@" + includes},
+			want:    []string{"run", "--", "@" + includes},
+			origins: []int{0, 1, 2},
+		},
+		{
+			name:    "no expansion after terminator in response file",
+			args:    []string{"@" + terminated, "@" + includes},
+			want:    []string{"--", "@" + includes, "@" + includes},
+			origins: []int{0, 0, 1},
+		},
+		{
+			name:    "missing response file",
+			args:    []string{"@" + filepath.Join(dir, "missing.txt")},
+			wantErr: "no such file",
+		},
+		{
+			name:    "recursive response file",
+			args:    []string{"@" + loop},
+			wantErr: "exceeded max depth",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, origins, err := lexer.ExpandResponseFiles(tt.args, 5)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(origins, tt.origins) {
+				t.Errorf("got origins %v, want %v", origins, tt.origins)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+// Package lineedit reads lines from a terminal with support for editing, history
+// and tab completion. Input that is not a terminal is read line by line as is.
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// key codes of the control characters the editor handles
+const (
+	keyCtrlA     = 1
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlE     = 5
+	keyCtrlH     = 8
+	keyTab       = 9
+	keyLF        = 10
+	keyCtrlK     = 11
+	keyCtrlL     = 12
+	keyCR        = 13
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyBackspace = 127
+)
+
+// Editor reads lines, keeping a history of the lines read.
+type Editor struct {
+	// Complete returns the candidates for completing the last word of line, which is
+	// the text before the cursor. Candidates replace the last word in full.
+	Complete func(line string) []string
+
+	in      io.Reader
+	r       *bufio.Reader
+	out     io.Writer
+	history []string
+
+	// state of the line being edited
+	prompt string
+	buf    []rune
+	pos    int
+}
+
+// New returns an Editor reading from in and echoing to out.
+func New(in io.Reader, out io.Writer) *Editor {
+	return &Editor{in: in, r: bufio.NewReader(in), out: out}
+}
+
+// History returns the lines read so far, oldest first. Empty lines and lines
+// repeating the previous one are not recorded.
+func (e *Editor) History() []string {
+	return e.history
+}
+
+// ReadLine writes prompt and reads a line, without its line ending. If the input is a terminal,
+// it is put in raw mode until the line is read, see Edit. It returns io.EOF once the input
+// is exhausted.
+func (e *Editor) ReadLine(prompt string) (string, error) {
+	if f, ok := e.in.(*os.File); ok {
+		if restore, err := makeRaw(f.Fd()); err == nil {
+			defer restore()
+			return e.Edit(prompt)
+		}
+	}
+
+	if _, err := io.WriteString(e.out, prompt); err != nil {
+		return "", err
+	}
+	line, err := e.r.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+	line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+	e.record(line)
+	return line, nil
+}
+
+// Edit writes prompt and reads a line, interpreting the input as keys pressed on a terminal
+// in raw mode:
+//
+//   - left and right arrows, home and end, ctrl-a and ctrl-e move the cursor
+//   - up and down arrows move through the history
+//   - backspace and delete remove characters, ctrl-k and ctrl-u remove the rest of the line
+//     after or before the cursor
+//   - tab completes the word before the cursor, or lists the candidates if there is more than one
+//   - ctrl-c discards the line, ctrl-l clears the screen
+//   - ctrl-d deletes the character under the cursor, or returns io.EOF on an empty line
+func (e *Editor) Edit(prompt string) (string, error) {
+	e.prompt, e.buf, e.pos = prompt, nil, 0
+	// the line being edited is kept at the end of the history while moving through it
+	history := append(e.history[:len(e.history):len(e.history)], "")
+	idx := len(history) - 1
+
+	if err := e.refresh(); err != nil {
+		return "", err
+	}
+read:
+	for {
+		r, _, err := e.r.ReadRune()
+		if err != nil {
+			if errors.Is(err, io.EOF) && len(e.buf) > 0 {
+				break read
+			}
+			return "", err
+		}
+
+		switch r {
+		case keyCR, keyLF:
+			break read
+		case keyCtrlC:
+			e.buf, e.pos = nil, 0
+			_, _ = io.WriteString(e.out, "^C\r\n")
+			history[len(history)-1], idx = "", len(history)-1
+		case keyCtrlD:
+			if len(e.buf) == 0 {
+				_, _ = io.WriteString(e.out, "\r\n")
+				return "", io.EOF
+			}
+			e.delete(e.pos)
+		case keyBackspace, keyCtrlH:
+			if e.pos > 0 {
+				e.pos--
+				e.delete(e.pos)
+			}
+		case keyCtrlA:
+			e.pos = 0
+		case keyCtrlE:
+			e.pos = len(e.buf)
+		case keyCtrlK:
+			e.buf = e.buf[:e.pos]
+		case keyCtrlU:
+			e.buf, e.pos = e.buf[e.pos:], 0
+		case keyCtrlL:
+			_, _ = io.WriteString(e.out, "\x1b[H\x1b[2J")
+		case keyTab:
+			e.complete()
+		case keyEscape:
+			switch e.escape() {
+			case 'A':
+				if idx > 0 {
+					history[idx] = string(e.buf)
+					idx--
+					e.buf = []rune(history[idx])
+					e.pos = len(e.buf)
+				}
+			case 'B':
+				if idx < len(history)-1 {
+					history[idx] = string(e.buf)
+					idx++
+					e.buf = []rune(history[idx])
+					e.pos = len(e.buf)
+				}
+			case 'C':
+				if e.pos < len(e.buf) {
+					e.pos++
+				}
+			case 'D':
+				if e.pos > 0 {
+					e.pos--
+				}
+			case 'H':
+				e.pos = 0
+			case 'F':
+				e.pos = len(e.buf)
+			case '~':
+				if e.pos < len(e.buf) {
+					e.delete(e.pos)
+				}
+			}
+		default:
+			if unicode.IsPrint(r) {
+				e.buf = append(e.buf[:e.pos], append([]rune{r}, e.buf[e.pos:]...)...)
+				e.pos++
+			}
+		}
+		if err := e.refresh(); err != nil {
+			return "", err
+		}
+	}
+	if _, err := io.WriteString(e.out, "\r\n"); err != nil {
+		return "", err
+	}
+	line := string(e.buf)
+	e.record(line)
+	return line, nil
+}
+
+// escape reads the rest of an escape sequence, returning the final character of the ones
+// the editor handles, ie: 'A' for the up arrow, ESC [ A, or '~' for delete, ESC [ 3 ~.
+// It returns 0 for any other sequence.
+func (e *Editor) escape() rune {
+	r, _, err := e.r.ReadRune()
+	if err != nil || (r != '[' && r != 'O') {
+		return 0
+	}
+	var param []rune
+	for {
+		r, _, err = e.r.ReadRune()
+		if err != nil {
+			return 0
+		}
+		if (r < '0' || r > '9') && r != ';' {
+			break
+		}
+		param = append(param, r)
+	}
+	switch {
+	case r == '~' && string(param) == "3":
+		return '~'
+	case r == '~' && (string(param) == "1" || string(param) == "7"):
+		return 'H'
+	case r == '~' && (string(param) == "4" || string(param) == "8"):
+		return 'F'
+	case len(param) == 0 && strings.ContainsRune("ABCDHF", r):
+		return r
+	}
+	return 0
+}
+
+// complete replaces the word before the cursor with the longest prefix shared by the
+// candidates for it, followed by a space if there is only one. If that adds nothing,
+// the candidates are listed below the line.
+func (e *Editor) complete() {
+	if e.Complete == nil {
+		return
+	}
+	line := string(e.buf[:e.pos])
+	candidates := e.Complete(line)
+	if len(candidates) == 0 {
+		return
+	}
+
+	start := strings.LastIndexAny(line, " \t") + 1
+	word := line[start:]
+	prefix := candidates[0]
+	if len(candidates) == 1 {
+		prefix += " "
+	}
+	for _, c := range candidates[1:] {
+		for !strings.HasPrefix(c, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	for !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+	if len(prefix) > len(word) && strings.HasPrefix(prefix, word) || len(candidates) == 1 {
+		completed := []rune(line[:start] + prefix)
+		e.buf = append(completed, e.buf[e.pos:]...)
+		e.pos = len(completed)
+		return
+	}
+
+	_, _ = io.WriteString(e.out, "\r\n"+strings.Join(candidates, "  ")+"\r\n")
+}
+
+// delete removes the character at i
+func (e *Editor) delete(i int) {
+	e.buf = append(e.buf[:i], e.buf[i+1:]...)
+}
+
+// refresh redraws the prompt and line, and moves the cursor to its position
+func (e *Editor) refresh() error {
+	s := "\r" + e.prompt + string(e.buf) + "\x1b[K"
+	if n := len(e.buf) - e.pos; n > 0 {
+		s += fmt.Sprintf("\x1b[%dD", n)
+	}
+	_, err := io.WriteString(e.out, s)
+	return err
+}
+
+// record adds line to the history
+func (e *Editor) record(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if len(e.history) > 0 && e.history[len(e.history)-1] == line {
+		return
+	}
+	e.history = append(e.history, line)
+}
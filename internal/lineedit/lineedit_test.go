@@ -0,0 +1,136 @@
+package lineedit_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jimmykodes/gommand/internal/lineedit"
+)
+
+const (
+	up    = "\x1b[A"
+	down  = "\x1b[B"
+	right = "\x1b[C"
+	left  = "\x1b[D"
+	home  = "\x1b[H"
+	del   = "\x1b[3~"
+)
+
+func TestEdit(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{name: "lines", input: "status\rserver run\n", want: []string{"status", "server run"}},
+		{name: "backspace", input: "statsu\x7f\x7fus\r", want: []string{"status"}},
+		{name: "cursor movement", input: "sttus" + left + left + left + "a" + "\x05!\r", want: []string{"status!"}},
+		{name: "home and delete", input: "xstatus" + home + del + "\r", want: []string{"status"}},
+		{name: "kill", input: "server run" + left + left + left + left + "\x0b\r" + "server run" + left + left + left + "\x15\r", want: []string{"server", "run"}},
+		{name: "interrupt", input: "server\x03status\r", want: []string{"status"}},
+		{name: "history", input: "status\rversion\r" + up + up + "\r", want: []string{"status", "version", "status"}},
+		{name: "history edit", input: "status\rversion\r" + up + up + " -w" + down + up + "\r", want: []string{"status", "version", "status -w"}},
+		{name: "history bounds", input: "status\r" + up + up + down + down + "x\r", want: []string{"status", "x"}},
+		{name: "unicode", input: "héllo" + left + "\x7f\r", want: []string{"hélo"}},
+		{name: "unterminated line", input: "status", want: []string{"status"}},
+		{name: "ctrl-d deletes", input: "xstatus\x01\x04\r", want: []string{"status"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := lineedit.New(strings.NewReader(tt.input), io.Discard)
+			var got []string
+			for {
+				line, err := e.Edit("> ")
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				got = append(got, line)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditComplete(t *testing.T) {
+	words := []string{"server", "service", "status"}
+	complete := func(line string) []string {
+		fields := strings.Fields(line)
+		if len(fields) > 1 || strings.HasSuffix(line, " ") {
+			return nil
+		}
+		var prefix string
+		if len(fields) == 1 {
+			prefix = fields[0]
+		}
+		var candidates []string
+		for _, w := range words {
+			if strings.HasPrefix(w, prefix) {
+				candidates = append(candidates, w)
+			}
+		}
+		return candidates
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		want     string
+		wantList bool
+	}{
+		{name: "single candidate", input: "st\t\r", want: "status "},
+		{name: "common prefix", input: "se\t\r", want: "serv"},
+		{name: "ambiguous lists candidates", input: "serv\t\r", want: "serv", wantList: true},
+		{name: "no candidates", input: "x\t\r", want: "x"},
+		{name: "before cursor", input: "sta -w" + left + left + left + "\t\r", want: "status  -w"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			e := lineedit.New(strings.NewReader(tt.input), &out)
+			e.Complete = complete
+			got, err := e.Edit("> ")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if listed := strings.Contains(out.String(), "server  service"); listed != tt.wantList {
+				t.Errorf("candidates listed: %v, want %v", listed, tt.wantList)
+			}
+		})
+	}
+}
+
+func TestReadLine(t *testing.T) {
+	var out strings.Builder
+	e := lineedit.New(strings.NewReader("status\r\n\nserver run\nserver run\nversion"), &out)
+	var got []string
+	for {
+		line, err := e.ReadLine("> ")
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		got = append(got, line)
+	}
+	if want := []string{"status", "", "server run", "server run", "version"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if want := []string{"status", "server run", "version"}; !reflect.DeepEqual(e.History(), want) {
+		t.Errorf("got history %q, want %q", e.History(), want)
+	}
+	if want := strings.Repeat("> ", 6); out.String() != want {
+		t.Errorf("got output %q, want %q", out.String(), want)
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !(darwin || dragonfly || freebsd || linux || netbsd || openbsd)
+
+package lineedit
+
+import "errors"
+
+// makeRaw is not supported on this platform, so input is always read line by line
+func makeRaw(uintptr) (restore func(), err error) {
+	return nil, errors.New("lineedit: raw mode is not supported")
+}
@@ -0,0 +1,86 @@
+// Package shellwords splits strings into words using a subset of POSIX shell
+// quoting rules. No expansion of any kind is performed.
+package shellwords
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrUnterminatedQuote  = errors.New("shellwords: unterminated quote")
+	ErrUnterminatedEscape = errors.New("shellwords: unterminated escape")
+)
+
+// Split splits s into words.
+//
+//   - words are separated by unquoted whitespace
+//   - a backslash outside of quotes escapes the following character, and a
+//     backslash followed by a newline is removed entirely
+//   - single quotes preserve every character up to the closing quote
+//   - double quotes preserve every character up to the closing quote, except
+//     a backslash followed by one of $ ` " \ or a newline, which escapes it
+//   - an unquoted # at the start of a word begins a comment, which runs to the
+//     end of the line
+func Split(s string) ([]string, error) {
+	var (
+		words  []string
+		word   strings.Builder
+		inWord bool
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '#' && !inWord:
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '\\':
+			i++
+			if i >= len(s) {
+				return nil, ErrUnterminatedEscape
+			}
+			if s[i] == '\n' {
+				continue
+			}
+			word.WriteByte(s[i])
+			inWord = true
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, ErrUnterminatedQuote
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, ErrUnterminatedQuote
+			}
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,45 @@
+package shellwords_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jimmykodes/gommand/internal/shellwords"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr error
+	}{
+		{name: "empty", input: "", want: nil},
+		{name: "whitespace", input: " \t\n ", want: nil},
+		{name: "simple", input: "deploy --env prod", want: []string{"deploy", "--env", "prod"}},
+		{name: "newlines", input: "--include a\n--include b\n", want: []string{"--include", "a", "--include", "b"}},
+		{name: "single quotes", input: `echo 'hello  world' '$HOME \n'`, want: []string{"echo", "hello  world", `$HOME \n`}},
+		{name: "double quotes", input: `echo "hello \"world\"" "a\b"`, want: []string{"echo", `hello "world"`, `a\b`}},
+		{name: "empty quotes", input: `a "" ''`, want: []string{"a", "", ""}},
+		{name: "adjacent quotes", input: `--name="a b"'c d'e`, want: []string{"--name=a bc de"}},
+		{name: "escapes", input: `a\ b \#c \\`, want: []string{"a b", "#c", `\`}},
+		{name: "line continuation", input: "a \\\nb", want: []string{"a", "b"}},
+		{name: "comments", input: "# comment\na # trailing\nb#c", want: []string{"a", "b#c"}},
+		{name: "unterminated single quote", input: `'abc`, wantErr: shellwords.ErrUnterminatedQuote},
+		{name: "unterminated double quote", input: `"abc`, wantErr: shellwords.ErrUnterminatedQuote},
+		{name: "unterminated escape", input: `abc\`, wantErr: shellwords.ErrUnterminatedEscape},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := shellwords.Split(tt.input)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Split(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Split(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+// Package suggest contains helpers for suggesting alternatives to user input.
+package suggest
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// JoinOr joins strs into a human readable list of alternatives. ie: a, b or c
+func JoinOr(strs []string) string {
+	if len(strs) < 2 {
+		return strings.Join(strs, "")
+	}
+	return strings.Join(strs[:len(strs)-1], ", ") + " or " + strs[len(strs)-1]
+}
+
+// maxDistance is the largest edit distance at which a candidate is still considered
+// a likely match for the input
+const maxDistance = 2
+
+// minPrefix is the shortest input for which the candidates it is a prefix of are suggested
+const minPrefix = 2
+
+// Closest returns the candidates that are likely what was meant by input, either because
+// they are within a small edit distance of it, or because input is a prefix of them.
+// To keep short names from matching nearly anything, the edit distance must be less than half
+// the length of the longer of input and the candidate, and input must be at least minPrefix
+// characters long to match as a prefix.
+// Results are ordered from closest to furthest.
+func Closest(input string, candidates []string) []string {
+	type match struct {
+		candidate string
+		distance  int
+	}
+	var matches []match
+	for _, c := range candidates {
+		d := distance(input, c)
+		similar := d <= maxDistance && d*2 < max(utf8.RuneCountInString(input), utf8.RuneCountInString(c))
+		if similar || (utf8.RuneCountInString(input) >= minPrefix && strings.HasPrefix(c, input)) {
+			matches = append(matches, match{candidate: c, distance: d})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.candidate, b.candidate)
+	})
+	out := make([]string, len(matches))
+	for i, m := range matches {
+		out[i] = m.candidate
+	}
+	return out
+}
+
+// distance returns the optimal string alignment distance between a and b, which is the
+// number of insertions, deletions, substitutions and transpositions of adjacent characters
+// needed to turn a into b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// d[i][j] is the distance between ra[:i] and rb[:j]
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
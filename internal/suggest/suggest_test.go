@@ -0,0 +1,50 @@
+package suggest_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/jimmykodes/gommand/internal/suggest"
+)
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"server", "serve", "status", "port", "protocol", "version", "st", "ls"}
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{input: "sever", want: []string{"server", "serve"}},
+		{input: "stauts", want: []string{"status"}},
+		{input: "prot", want: []string{"port", "protocol"}},
+		{input: "vers", want: []string{"version"}},
+		{input: "xyz", want: []string{}},
+		{input: "s", want: []string{}},
+		{input: "sx", want: []string{}},
+		{input: "sta", want: []string{"st", "status"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := suggest.Closest(tt.input, candidates); !slices.Equal(got, tt.want) {
+				t.Errorf("Closest(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinOr(t *testing.T) {
+	tests := []struct {
+		strs []string
+		want string
+	}{
+		{strs: nil, want: ""},
+		{strs: []string{"a"}, want: "a"},
+		{strs: []string{"a", "b"}, want: "a or b"},
+		{strs: []string{"a", "b", "c"}, want: "a, b or c"},
+	}
+	for _, tt := range tests {
+		if got := suggest.JoinOr(tt.strs); got != tt.want {
+			t.Errorf("JoinOr(%v) = %q, want %q", tt.strs, got, tt.want)
+		}
+	}
+}
@@ -29,3 +29,56 @@ func WithStderr(stderr io.Writer) ExecutionOptionFunc {
 		ctx.stderr = stderr
 	}
 }
+
+// WithArgs sets the arguments the command tree is executed against.
+// By default, os.Args[1:] are used.
+func WithArgs(args []string) ExecutionOptionFunc {
+	return func(ctx *Context) {
+		ctx.argv = args
+	}
+}
+
+// WithResponseFiles enables response files. Any argument of the form `@path` is
+// replaced by the shell-word split contents of the file at path before parsing.
+// Response files may reference other response files, up to maxDepth levels deep.
+//
+// Lines in a response file beginning with # are ignored, and a literal argument
+// beginning with @ can be passed by escaping it as @@, ie: @@handle -> @handle
+func WithResponseFiles(maxDepth int) ExecutionOptionFunc {
+	return func(ctx *Context) {
+		ctx.responseFileDepth = maxDepth
+	}
+}
+
+// WithHelpCommand registers a `help [command...]` subcommand on the executed command that
+// prints the help text of the command at the given path, resolving aliases along the way.
+// ie: `mytool help server run` prints the same help text as `mytool server run --help`
+//
+// The subcommand is only registered for the duration of the execution. If the command
+// already has a subcommand called help, it is left as is.
+func WithHelpCommand() ExecutionOptionFunc {
+	return func(ctx *Context) {
+		ctx.helpCommand = true
+	}
+}
+
+// WithCompletionCommand registers a `completion {bash|zsh|fish}` subcommand on the executed
+// command that prints a completion script for the command tree, see Command.GenCompletion,
+// along with the hidden __complete command the scripts call to complete the values of args
+// and flags using Command.Complete and flags.Completion callbacks.
+//
+// The subcommands are only registered for the duration of the execution. If the command
+// already has a subcommand called completion or __complete, it is left as is.
+func WithCompletionCommand() ExecutionOptionFunc {
+	return func(ctx *Context) {
+		ctx.completionCommand = true
+	}
+}
+
+// WithPrompt sets the prompt written before each line read by Command.REPL.
+// By default, it is the name of the command followed by "> ".
+func WithPrompt(prompt string) ExecutionOptionFunc {
+	return func(ctx *Context) {
+		ctx.prompt = prompt
+	}
+}
@@ -0,0 +1,99 @@
+package gommand
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/jimmykodes/gommand/flags"
+)
+
+// pluginPrefix returns the prefix of the names of the executables that are plugins of c,
+// or an empty string if c does not have plugins enabled. ie: mytool-
+func (c *Command) pluginPrefix() string {
+	if !c.Plugins || c.parent != nil {
+		return ""
+	}
+	return c.name() + "-"
+}
+
+// lookupPlugin returns the path of the executable of the plugin called name, searching
+// PluginDirs then $PATH, or false if there is none.
+func (c *Command) lookupPlugin(name string) (string, bool) {
+	prefix := c.pluginPrefix()
+	if prefix == "" || name == "" || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	for _, dir := range c.PluginDirs {
+		if path, err := exec.LookPath(filepath.Join(dir, prefix+name)); err == nil {
+			return path, true
+		}
+	}
+	path, err := exec.LookPath(prefix + name)
+	return path, err == nil
+}
+
+// plugins returns the names of the plugins found in PluginDirs and on $PATH, sorted by name.
+// Plugins shadowed by a subcommand are left out, as they can not be executed.
+func (c *Command) plugins() []string {
+	prefix := c.pluginPrefix()
+	if prefix == "" {
+		return nil
+	}
+
+	var names []string
+	for _, dir := range slices.Concat(c.PluginDirs, filepath.SplitList(os.Getenv("PATH"))) {
+		entries, _ := os.ReadDir(dir)
+		for _, entry := range entries {
+			name, ok := strings.CutPrefix(entry.Name(), prefix)
+			if !ok || name == "" || entry.IsDir() {
+				continue
+			}
+			if runtime.GOOS == "windows" {
+				name = strings.TrimSuffix(name, filepath.Ext(name))
+			}
+			if _, ok := c.commands[name]; ok || slices.Contains(names, name) {
+				continue
+			}
+			if _, ok := c.lookupPlugin(name); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// runPlugin executes the plugin called name, found at path, with the args that have not
+// been parsed yet. The values of the persistent flags parsed so far are passed to the plugin
+// as environment variables, named as flags.Environ reads them.
+func (c *Command) runPlugin(ctx *Context, name, path string) error {
+	env := os.Environ()
+	for _, f := range flags.NewFlagGetter(ctx.persistentFlags()).All() {
+		if !f.IsSet() {
+			if err := flags.SetFromSources(f); err != nil {
+				return err
+			}
+		}
+		if f.IsSet() {
+			env = append(env, flags.EnvName(f.Name())+"="+flags.Format(f))
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, path, ctx.lexer.Rest()...)
+	cmd.Stdin = ctx.Stdin()
+	cmd.Stdout = ctx.Stdout()
+	cmd.Stderr = ctx.Stderr()
+	cmd.Env = env
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return ErrPluginExit{Plugin: name, Code: exitErr.ExitCode(), Err: err}
+	}
+	return err
+}
@@ -0,0 +1,138 @@
+package gommand_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
+)
+
+func TestPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are shell scripts")
+	}
+	writePlugin := func(t *testing.T, dir, name, script string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pluginDir, pathDir := t.TempDir(), t.TempDir()
+	writePlugin(t, pluginDir, "mytool-hello", `echo "args: $*"; echo "cluster: $CLUSTER"; echo "tags: $TAGS"; while read -r line; do echo "$line"; done`)
+	writePlugin(t, pluginDir, "mytool-fail", "echo failing >&2; exit 3")
+	writePlugin(t, pathDir, "mytool-path", "echo from path")
+	writePlugin(t, pathDir, "mytool-status", "echo shadowed")
+	if err := os.WriteFile(filepath.Join(pathDir, "mytool-notexec"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", pathDir)
+	for _, env := range []string{"CLUSTER", "TAGS"} {
+		t.Setenv(env, "")
+		_ = os.Unsetenv(env)
+	}
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+		want  string
+	}{
+		{
+			name:  "plugin dir",
+			args:  []string{"--cluster", "prod", "--tags=a,b", "hello", "--name", "x", "--help"},
+			stdin: "from stdin\n",
+			want:  "args: --name x --help\ncluster: prod\ntags: a,b\nfrom stdin\n",
+		},
+		{name: "unset flags are not forwarded", args: []string{"hello"}, want: "args: \ncluster: \ntags: \n"},
+		{name: "path", args: []string{"path"}, want: "from path\n"},
+		{name: "subcommands take precedence", args: []string{"status"}, want: "builtin\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &gommand.Command{
+				Name:       "mytool",
+				Plugins:    true,
+				PluginDirs: []string{pluginDir},
+				PersistentFlagSet: flags.NewFlagSet().AddFlags(
+					flags.StringFlag("cluster", "local", "cluster to use"),
+					flags.StringSliceFlag("tags", nil, "tags"),
+				),
+			}
+			root.SubCommand(&gommand.Command{Name: "status", Usage: "show status", Run: func(ctx *gommand.Context) error {
+				_, err := ctx.Stdout().Write([]byte("builtin\n"))
+				return err
+			}})
+
+			var buf bytes.Buffer
+			err := root.ExecuteArgs(context.Background(), tt.args, gommand.WithStdout(&buf), gommand.WithStdin(strings.NewReader(tt.stdin)))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("exit code", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", Plugins: true, PluginDirs: []string{pluginDir}}
+
+		var stderr bytes.Buffer
+		err := root.ExecuteArgs(context.Background(), []string{"fail"}, gommand.WithStderr(&stderr))
+		var exitErr gommand.ErrPluginExit
+		if !errors.As(err, &exitErr) {
+			t.Fatalf("expected ErrPluginExit, got %T: %v", err, err)
+		}
+		if exitErr.Plugin != "fail" || exitErr.Code != 3 {
+			t.Errorf("got plugin %q code %d, want fail 3", exitErr.Plugin, exitErr.Code)
+		}
+		if got := stderr.String(); got != "failing\n" {
+			t.Errorf("expected only the plugin's output on stderr, got %q", got)
+		}
+	})
+
+	t.Run("listed in help", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", Plugins: true, PluginDirs: []string{pluginDir}}
+		root.SubCommand(&gommand.Command{Name: "status", Run: func(*gommand.Context) error { return nil }})
+
+		var buf bytes.Buffer
+		if err := root.ExecuteArgs(context.Background(), []string{"--help"}, gommand.WithStdout(&buf)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := "Plugins:\n  fail\n  hello\n  path\n\n"; !strings.Contains(buf.String(), want) {
+			t.Errorf("expected help text to contain %q, got:\n%s", want, buf.String())
+		}
+		if got := root.HelpData().Plugins; got != nil {
+			t.Errorf("expected HelpData to leave out the plugins, got %q", got)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", PluginDirs: []string{pluginDir}}
+		root.SubCommand(&gommand.Command{Name: "status", Run: func(*gommand.Context) error { return nil }})
+
+		if err := root.ExecuteArgs(context.Background(), []string{"hello"}, gommand.WithStderr(&bytes.Buffer{})); err == nil {
+			t.Error("expected error with plugins disabled")
+		}
+		if got := root.HelpData().Plugins; got != nil {
+			t.Errorf("expected no plugins, got %q", got)
+		}
+	})
+
+	t.Run("only on the root", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", Plugins: true, PluginDirs: []string{pluginDir}}
+		sub := &gommand.Command{Name: "sub", Plugins: true, PluginDirs: []string{pluginDir}, Run: func(*gommand.Context) error { return nil }}
+		root.SubCommand(sub)
+		if err := root.ExecuteArgs(context.Background(), []string{"sub", "hello"}, gommand.WithStderr(&bytes.Buffer{})); err == nil {
+			t.Error("expected error for plugin of a subcommand")
+		}
+	})
+}
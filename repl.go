@@ -0,0 +1,128 @@
+package gommand
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/jimmykodes/gommand/flags"
+	"github.com/jimmykodes/gommand/internal/lineedit"
+	"github.com/jimmykodes/gommand/internal/shellwords"
+)
+
+// REPL runs an interactive session over the command tree rooted at c. Lines are read from the
+// Context's Stdin, split into words using shell quoting rules, and executed against the tree
+// as if they had been passed on the command line, with opts applied to each execution.
+//
+// Values of persistent flags are kept from one line to the next, so they only need to be passed
+// once per session, while every other flag starts each line from its default value. Errors are
+// reported as they would be by ExecuteContext, without ending the session.
+//
+// When Stdin is a terminal, lines can be edited, previous lines recalled with the up and down
+// arrows, and commands, flags and values completed with tab, see Command.Complete.
+//
+// The session ends at the end of the input, when ctx is done, or on a line reading exit or quit,
+// unless c has a subcommand with that name. The prompt defaults to the name of c, see WithPrompt.
+func (c *Command) REPL(ctx context.Context, opts ...ExecutionOption) error {
+	base := &Context{Context: ctx, cmd: c}
+	for _, opt := range opts {
+		opt.Apply(base)
+	}
+	prompt := base.prompt
+	if prompt == "" {
+		prompt = c.name() + "> "
+	}
+
+	editor := lineedit.New(base.Stdin(), base.Stdout())
+	editor.Complete = func(line string) []string {
+		return c.replComplete(base, line)
+	}
+
+	opts = slices.Clip(opts)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line, err := editor.ReadLine(prompt)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		words, err := shellwords.Split(line)
+		if err != nil {
+			_, _ = fmt.Fprintln(base.Stderr(), "Error:", err)
+			continue
+		}
+		if len(words) == 0 {
+			continue
+		}
+		if _, ok := c.commands[words[0]]; !ok && len(words) == 1 && (words[0] == "exit" || words[0] == "quit") {
+			return nil
+		}
+
+		c.walkTree(func(cmd *Command) {
+			resetFlags(cmd.FlagSet)
+		})
+		_ = c.ExecuteContext(ctx, append(opts, WithArgs(words))...)
+	}
+}
+
+// replComplete returns the candidates for the last word of line, or the word following it
+// if line ends in a space.
+func (c *Command) replComplete(base *Context, line string) []string {
+	words, err := shellwords.Split(line)
+	if err != nil {
+		return nil
+	}
+	var toComplete string
+	if len(words) > 0 && !strings.HasSuffix(line, " ") {
+		words, toComplete = words[:len(words)-1], words[len(words)-1]
+	}
+
+	// completing parses the words, setting any flags in them, which should not
+	// outlive the line being edited
+	var restore []func() error
+	c.walkTree(func(cmd *Command) {
+		for _, fs := range []*flags.FlagSet{cmd.FlagSet, cmd.PersistentFlagSet} {
+			if fs == nil {
+				continue
+			}
+			for _, f := range flags.NewFlagGetter(fs).All() {
+				restore = append(restore, flags.Snapshot(f))
+			}
+		}
+	})
+	candidates, _ := c.complete(base, words, toComplete)
+	for _, r := range restore {
+		_ = r()
+	}
+
+	for i, candidate := range candidates {
+		candidates[i], _, _ = strings.Cut(candidate, "\t")
+	}
+	return candidates
+}
+
+// walkTree calls fn for c and every command below it
+func (c *Command) walkTree(fn func(*Command)) {
+	fn(c)
+	for _, sub := range c.children {
+		sub.walkTree(fn)
+	}
+}
+
+// resetFlags returns the flags in fs to their default values
+func resetFlags(fs *flags.FlagSet) {
+	if fs == nil {
+		return
+	}
+	for _, f := range flags.NewFlagGetter(fs).All() {
+		flags.Reset(f)
+	}
+}
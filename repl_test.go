@@ -0,0 +1,148 @@
+package gommand_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
+)
+
+func TestREPL(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		opts       []gommand.ExecutionOption
+		want       string
+		wantStderr string
+	}{
+		{
+			name:  "persistent flags are kept",
+			input: "status\n--cluster prod status -w\nstatus 'a b'\n",
+			want:  "mytool> status cluster=local watch=false args=[]\nmytool> status cluster=prod watch=true args=[]\nmytool> status cluster=prod watch=false args=[\"a b\"]\nmytool> ",
+		},
+		{
+			name:       "errors do not end the session",
+			input:      "fail\nstatus 'a\n\nstatus\n",
+			want:       "mytool> mytool> mytool> mytool> status cluster=local watch=false args=[]\nmytool> ",
+			wantStderr: "Error: failed\nError: shellwords: unterminated quote\n",
+		},
+		{
+			name:  "exit",
+			input: "status\nexit\nstatus\n",
+			want:  "mytool> status cluster=local watch=false args=[]\nmytool> ",
+		},
+		{
+			name:  "prompt",
+			input: "status\n",
+			opts:  []gommand.ExecutionOption{gommand.WithPrompt("$ ")},
+			want:  "$ status cluster=local watch=false args=[]\n$ ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			root := &gommand.Command{
+				Name:              "mytool",
+				SilenceHelp:       true,
+				PersistentFlagSet: flags.NewFlagSet().AddFlags(flags.StringFlag("cluster", "local", "cluster to use")),
+			}
+			root.SubCommand(
+				&gommand.Command{
+					Name:         "status",
+					ArgValidator: gommand.ArgsAny(),
+					FlagSet:      flags.NewFlagSet().AddFlags(flags.BoolFlagS("watch", 'w', false, "watch status")),
+					Run: func(ctx *gommand.Context) error {
+						_, err := fmt.Fprintf(&stdout, "status cluster=%s watch=%v args=%q\n", ctx.Flags().String("cluster"), ctx.Flags().Bool("watch"), ctx.Args())
+						return err
+					},
+				},
+				&gommand.Command{
+					Name: "fail",
+					Run:  func(*gommand.Context) error { return errors.New("failed") },
+				},
+			)
+
+			opts := append([]gommand.ExecutionOption{
+				gommand.WithStdin(strings.NewReader(tt.input)),
+				gommand.WithStdout(&stdout),
+				gommand.WithStderr(&stderr),
+			}, tt.opts...)
+			if err := root.REPL(context.Background(), opts...); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := stdout.String(); got != tt.want {
+				t.Errorf("got stdout:\n%q\nwant:\n%q", got, tt.want)
+			}
+			if got := stderr.String(); got != tt.wantStderr {
+				t.Errorf("got stderr:\n%q\nwant:\n%q", got, tt.wantStderr)
+			}
+		})
+	}
+
+	t.Run("exit subcommand", func(t *testing.T) {
+		var ran bool
+		root := &gommand.Command{Name: "mytool"}
+		root.SubCommand(&gommand.Command{Name: "exit", Run: func(*gommand.Context) error { ran = true; return nil }})
+
+		err := root.REPL(context.Background(), gommand.WithStdin(strings.NewReader("exit\n")), gommand.WithStdout(&bytes.Buffer{}))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !ran {
+			t.Error("expected exit subcommand to run")
+		}
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		root := &gommand.Command{Name: "mytool"}
+		root.SubCommand(&gommand.Command{Name: "status", Run: func(*gommand.Context) error { return nil }})
+
+		err := root.REPL(ctx, gommand.WithStdin(strings.NewReader("status\n")), gommand.WithStdout(&bytes.Buffer{}))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want context.Canceled", err)
+		}
+	})
+}
+
+func TestREPLComplete(t *testing.T) {
+	tags := flags.StringSliceFlag("tags", nil, "tags")
+	root := &gommand.Command{
+		Name: "mytool",
+		PersistentFlagSet: flags.NewFlagSet().AddFlags(
+			flags.StringFlag("profile", "default", "profile to use"),
+			flags.CountFlagS("verbose", 'v', 0, "verbosity"),
+		),
+	}
+	root.SubCommand(&gommand.Command{
+		Name:    "status",
+		FlagSet: flags.NewFlagSet().AddFlags(tags),
+		Run:     func(*gommand.Context) error { return nil },
+	})
+	pfs := root.PersistentFlagSet
+	if err := pfs.FromName("profile").Set("prod"); err != nil {
+		t.Fatal(err)
+	}
+	if err := flags.Increment(pfs.FromName("verbose")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{"--profile other -vv st", "--profile other -v status --tags a,b "} {
+		root.ReplComplete(line)
+	}
+	if got := pfs.FromName("profile").Value(); got != "prod" {
+		t.Errorf("got profile %v, want prod", got)
+	}
+	if got := pfs.FromName("verbose").Value(); got != 1 {
+		t.Errorf("got verbose %v, want 1", got)
+	}
+	if tags.IsSet() {
+		t.Errorf("got tags %v, want them unset", tags.Value())
+	}
+}
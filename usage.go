@@ -0,0 +1,420 @@
+package gommand
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jimmykodes/gommand/flags"
+)
+
+// usage returns the usage descriptions of the command, the part of its Name after the name
+// itself, or the synopsis of its Args if its Name has none. ie: [-d destination] file_name...
+func (c *Command) usage() string {
+	if _, usage, ok := strings.Cut(c.Name, " "); ok {
+		return usage
+	}
+	synopses := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		synopses[i] = arg.Synopsis()
+	}
+	return strings.Join(synopses, " ")
+}
+
+// usageElem is an element of a usage grammar, as documented on Command.Name. It is either
+// a flag, a positional arg, or a group of alternatives.
+type usageElem struct {
+	// text is the part of the usage the element was parsed from, starting at offset start
+	text  string
+	start int
+
+	// flag is the name of the flag, without dashes, if the element is a flag
+	flag  string
+	short bool
+
+	// alts are the alternatives of a group, each a sequence of elements
+	alts     [][]*usageElem
+	optional bool
+
+	variadic bool
+}
+
+func (e *usageElem) isGroup() bool { return e.alts != nil }
+
+// args returns the minimum and maximum number of positional args matched by the element
+func (e *usageElem) args() (lower, upper int) {
+	switch {
+	case e.isGroup():
+		lower, upper = math.MaxInt, 0
+		for _, alt := range e.alts {
+			l, u := seqArgs(alt)
+			lower, upper = min(lower, l), max(upper, u)
+		}
+		if e.optional {
+			lower = 0
+		}
+	case e.flag == "":
+		lower, upper = 1, 1
+	}
+	if e.variadic && upper > 0 {
+		upper = math.MaxInt
+	}
+	return lower, upper
+}
+
+// seqArgs returns the minimum and maximum number of positional args matched by seq
+func seqArgs(seq []*usageElem) (lower, upper int) {
+	for _, e := range seq {
+		l, u := e.args()
+		lower += l
+		if u > math.MaxInt-upper {
+			upper = math.MaxInt
+		} else {
+			upper += u
+		}
+	}
+	return lower, upper
+}
+
+// flagNames returns the names of the flags in seq, including those in nested groups
+func flagNames(seq []*usageElem) []string {
+	var names []string
+	for _, e := range seq {
+		if e.flag != "" {
+			names = append(names, e.flag)
+		}
+		for _, alt := range e.alts {
+			names = append(names, flagNames(alt)...)
+		}
+	}
+	return names
+}
+
+// usageToken is a token of a usage grammar, along with its offset in the usage
+type usageToken struct {
+	value string
+	pos   int
+}
+
+// tokenizeUsage splits usage into words and the grammar's symbols: [ ] { } | and ...
+func tokenizeUsage(usage string) []usageToken {
+	var (
+		tokens []usageToken
+		start  = -1
+	)
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, usageToken{value: usage[start:end], pos: start})
+			start = -1
+		}
+	}
+	for i := 0; i < len(usage); i++ {
+		switch ch := usage[i]; {
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			flush(i)
+		case strings.IndexByte("[]{}|", ch) >= 0:
+			flush(i)
+			tokens = append(tokens, usageToken{value: usage[i : i+1], pos: i})
+		case strings.HasPrefix(usage[i:], "..."):
+			flush(i)
+			tokens = append(tokens, usageToken{value: "...", pos: i})
+			i += 2
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+	flush(len(usage))
+	return tokens
+}
+
+// usageParser parses a usage grammar, see Command.Name
+type usageParser struct {
+	usage  string
+	tokens []usageToken
+	pos    int
+	// takesValue reports whether the flag with the given name is followed by a value
+	takesValue func(name string, short bool) bool
+}
+
+// parseUsage parses usage into a sequence of elements
+func parseUsage(usage string, takesValue func(name string, short bool) bool) ([]*usageElem, error) {
+	p := &usageParser{usage: usage, tokens: tokenizeUsage(usage), takesValue: takesValue}
+	seq, err := p.seq()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q at offset %d", p.tokens[p.pos].value, p.tokens[p.pos].pos)
+	}
+	return seq, nil
+}
+
+// end returns the offset in the usage of the end of the last token read
+func (p *usageParser) end() int {
+	t := p.tokens[p.pos-1]
+	return t.pos + len(t.value)
+}
+
+// seq parses elements up to the end of the usage, or the next | or closing bracket
+func (p *usageParser) seq() ([]*usageElem, error) {
+	var (
+		seq     []*usageElem
+		pending bool // a leading ... applies to the next element. ie: [...n]
+	)
+	for p.pos < len(p.tokens) {
+		t := p.tokens[p.pos]
+		var e *usageElem
+		switch t.value {
+		case "|", "]", "}":
+			if pending {
+				return nil, fmt.Errorf("%q at offset %d is not followed by an argument", "...", t.pos)
+			}
+			return seq, nil
+		case "...":
+			p.pos++
+			if len(seq) == 0 {
+				pending = true
+			} else {
+				last := seq[len(seq)-1]
+				last.variadic = true
+				last.text = p.usage[last.start:p.end()]
+			}
+			continue
+		case "[", "{":
+			var err error
+			if e, err = p.group(); err != nil {
+				return nil, err
+			}
+		default:
+			p.pos++
+			e = &usageElem{text: t.value, start: t.pos}
+			if name, ok := strings.CutPrefix(t.value, "-"); ok && name != "" {
+				e.flag, e.short = strings.TrimPrefix(name, "-"), !strings.HasPrefix(name, "-")
+				var hasValue bool
+				e.flag, _, hasValue = strings.Cut(e.flag, "=")
+				if !hasValue && p.word() && p.takesValue(e.flag, e.short) {
+					p.pos++
+					e.text = p.usage[t.pos:p.end()]
+				}
+			}
+		}
+		if pending {
+			e.variadic, pending = true, false
+		}
+		seq = append(seq, e)
+	}
+	if pending {
+		return nil, fmt.Errorf("%q at the end of the usage is not followed by an argument", "...")
+	}
+	return seq, nil
+}
+
+// word reports whether the next token is a word, rather than a symbol or a flag
+func (p *usageParser) word() bool {
+	if p.pos >= len(p.tokens) {
+		return false
+	}
+	switch v := p.tokens[p.pos].value; v {
+	case "[", "]", "{", "}", "|", "...":
+		return false
+	default:
+		return !strings.HasPrefix(v, "-")
+	}
+}
+
+// group parses a [ ] or { } group of alternatives separated by |
+func (p *usageParser) group() (*usageElem, error) {
+	open := p.tokens[p.pos]
+	closer := map[string]string{"[": "]", "{": "}"}[open.value]
+	p.pos++
+
+	e := &usageElem{optional: open.value == "[", start: open.pos}
+	for {
+		alt, err := p.seq()
+		if err != nil {
+			return nil, err
+		}
+		if len(alt) == 0 {
+			return nil, fmt.Errorf("empty alternative in %q at offset %d", open.value, open.pos)
+		}
+		e.alts = append(e.alts, alt)
+		if p.pos >= len(p.tokens) {
+			return nil, fmt.Errorf("unclosed %q at offset %d", open.value, open.pos)
+		}
+		t := p.tokens[p.pos]
+		p.pos++
+		if t.value == closer {
+			break
+		}
+		if t.value != "|" {
+			return nil, fmt.Errorf("unexpected %q at offset %d, expected %q to close %q at offset %d", t.value, t.pos, closer, open.value, open.pos)
+		}
+	}
+	e.text = p.usage[open.pos:p.end()]
+	return e, nil
+}
+
+// parseUsage parses the usage of the command, using fs to tell which flags take a value
+func (c *Command) parseUsage(fs *flags.FlagSet) ([]*usageElem, error) {
+	return parseUsage(c.usage(), func(name string, short bool) bool {
+		f := usageFlag(fs, name, short)
+		return f != nil && takesValue(f)
+	})
+}
+
+// checkUsage reports whether the args, and the flags in fs, parsed for the command match its usage
+func (c *Command) checkUsage(ctx *Context, fs *flags.FlagSet) error {
+	usage := c.usage()
+	seq, err := c.parseUsage(fs)
+	if err != nil {
+		return fmt.Errorf("gommand: %s: invalid usage %q: %w", c.path(), usage, err)
+	}
+
+	// the flags in the usage can be set by their sources as well as the command line,
+	// so they are read before checking which flags are set
+	err = walkUsageFlags(seq, func(e *usageElem) error {
+		if f := usageFlag(fs, e.flag, e.short); f != nil && !f.IsSet() {
+			return flags.SetFromSources(f)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	last, _ := ctx.lexer.Last()
+	check := usageCheck{
+		path: c.path(),
+		end:  ctx.position(last + 1),
+		flag: func(e *usageElem) (set bool, pos int) {
+			f := usageFlag(fs, e.flag, e.short)
+			if f == nil || !f.IsSet() {
+				return false, -1
+			}
+			if pos, ok := ctx.flagPositions[f]; ok {
+				return true, pos
+			}
+			return true, -1
+		},
+	}
+
+	lower, upper := seqArgs(seq)
+	if n := len(ctx.args); n > upper {
+		return check.err(ctx.argPositions[upper], strings.TrimSpace(usage), "unexpected argument %q", ctx.args[upper])
+	} else if n < lower {
+		// report the first element that is missing an arg
+		var count int
+		for _, e := range seq {
+			l, _ := e.args()
+			if count += l; count > n {
+				return check.err(check.end, e.text, "missing required argument")
+			}
+		}
+	}
+	return check.flags(seq, true)
+}
+
+// usageCheck checks the flags of a command line against a usage
+type usageCheck struct {
+	path string
+	// end is the position after the last argument, where a missing part of the usage belongs
+	end int
+	// flag reports whether the flag of e is set, and its position if it was set on the command line
+	flag func(e *usageElem) (set bool, pos int)
+}
+
+func (u usageCheck) err(pos int, part, format string, a ...any) error {
+	return ErrUsage{Location: Location{CommandPath: u.path, Position: pos}, Usage: part, Msg: fmt.Sprintf(format, a...)}
+}
+
+// flags checks that the flags in seq that are required are set, and that flags in
+// different alternatives of a group are not set together.
+func (u usageCheck) flags(seq []*usageElem, required bool) error {
+	// firstSet returns the first flag of seq that is set, and the position of the last one
+	// set on the command line, or -1
+	firstSet := func(seq []*usageElem) (text string, pos int) {
+		pos = -1
+		_ = walkUsageFlags(seq, func(e *usageElem) error {
+			if set, p := u.flag(e); set {
+				if text == "" {
+					text = e.text
+				}
+				pos = max(pos, p)
+			}
+			return nil
+		})
+		return text, pos
+	}
+
+	var errs []error
+	for _, e := range seq {
+		if e.flag != "" {
+			if set, _ := u.flag(e); required && !set {
+				errs = append(errs, u.err(u.end, e.text, "missing required flag"))
+			}
+			continue
+		}
+		if !e.isGroup() {
+			continue
+		}
+
+		var (
+			active  []*usageElem
+			chosen  []string
+			pos     = -1
+			hasArgs bool
+		)
+		for _, alt := range e.alts {
+			if set, p := firstSet(alt); set != "" {
+				active, chosen, pos = alt, append(chosen, set), max(pos, p)
+			}
+			if _, upper := seqArgs(alt); upper > 0 || len(flagNames(alt)) == 0 {
+				hasArgs = true
+			}
+		}
+		switch {
+		case len(chosen) > 1:
+			// the alternative chosen last on the command line is the one in conflict
+			errs = append(errs, u.err(pos, e.text, "%s are mutually exclusive", strings.Join(chosen, " and ")))
+		case len(chosen) == 1:
+			// the flags of the chosen alternative are required along with it
+			if err := u.flags(active, true); err != nil {
+				errs = append(errs, err)
+			}
+		case required && !e.optional && !hasArgs:
+			errs = append(errs, u.err(u.end, e.text, "missing required flag"))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// walkUsageFlags calls fn for every flag in seq, including those in nested groups
+func walkUsageFlags(seq []*usageElem, fn func(*usageElem) error) error {
+	for _, e := range seq {
+		if e.flag != "" {
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		for _, alt := range e.alts {
+			if err := walkUsageFlags(alt, fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// usageFlag returns the flag named in a usage, or nil if there is none
+func usageFlag(fs *flags.FlagSet, name string, short bool) flags.Flag {
+	if !short {
+		return fs.FromName(name)
+	}
+	if r := []rune(name); len(r) == 1 {
+		return fs.FromShort(r[0])
+	}
+	return nil
+}
@@ -0,0 +1,190 @@
+package gommand_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
+)
+
+func TestEnforceUsage(t *testing.T) {
+	const create = "create {--from-file file | --from-gcs bucket} [-d destination] file_name..."
+
+	tests := []struct {
+		name      string
+		usage     string
+		args      []string
+		env       string
+		wantUsage string
+		wantMsg   string
+		wantPos   int
+	}{
+		{name: "valid", usage: create, args: []string{"--from-file", "a", "x", "y"}},
+		{name: "valid with optional flag", usage: create, args: []string{"--from-gcs=b", "-d", "out", "x"}},
+		{
+			name: "missing variadic", usage: create, args: []string{"--from-file", "a"},
+			wantUsage: "file_name...", wantMsg: "missing required argument", wantPos: 3,
+		},
+		{
+			name: "missing alternative", usage: create, args: []string{"x"},
+			wantUsage: "{--from-file file | --from-gcs bucket}", wantMsg: "missing required flag", wantPos: 2,
+		},
+		{
+			name: "mutually exclusive", usage: create, args: []string{"--from-file", "a", "--from-gcs", "b", "x"},
+			wantUsage: "{--from-file file | --from-gcs bucket}", wantMsg: "--from-file file and --from-gcs bucket are mutually exclusive", wantPos: 3,
+		},
+		{
+			name: "too many args", usage: "copy src [dest]", args: []string{"a", "b", "c"},
+			wantUsage: "src [dest]", wantMsg: `unexpected argument "c"`, wantPos: 3,
+		},
+		{name: "optional arg", usage: "copy src [dest]", args: []string{"a"}},
+		{name: "leading ellipsis", usage: "sum [...n]", args: []string{"1", "2", "3"}},
+		{name: "no usage accepts no args", usage: "sum", args: nil},
+		{
+			name: "no usage rejects args", usage: "sum", args: []string{"1"},
+			wantUsage: "", wantMsg: `unexpected argument "1"`, wantPos: 1,
+		},
+		{
+			name: "optional exclusive flags", usage: "rm [--force | --quiet] path", args: []string{"--force", "--quiet", "p"},
+			wantUsage: "[--force | --quiet]", wantMsg: "--force and --quiet are mutually exclusive", wantPos: 2,
+		},
+		{
+			name: "required flag", usage: "tag --name name", args: nil,
+			wantUsage: "--name name", wantMsg: "missing required flag", wantPos: 1,
+		},
+		{name: "required flag from env", usage: "tag --name name", env: "x"},
+		{
+			name: "exclusive with a flag from env", usage: "tag [--name name | --force]", args: []string{"--force"}, env: "x",
+			wantUsage: "[--name name | --force]", wantMsg: "--name name and --force are mutually exclusive", wantPos: 1,
+		},
+		{
+			name: "chosen alternative requires its flags", usage: "sync {--from-file file -d destination | --from-gcs bucket}", args: []string{"--from-file", "a"},
+			wantUsage: "-d destination", wantMsg: "missing required flag", wantPos: 3,
+		},
+		{name: "positional alternative", usage: "get {--name name | id}", args: []string{"1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NAME", tt.env)
+			if tt.env == "" {
+				os.Unsetenv("NAME")
+			}
+			root := &gommand.Command{Name: "mytool", EnforceUsage: true, SilenceHelp: true, SilenceError: true}
+			root.SubCommand(&gommand.Command{
+				Name: tt.usage,
+				FlagSet: flags.NewFlagSet().AddFlags(
+					flags.StringFlag("from-file", "", ""),
+					flags.StringFlag("from-gcs", "", ""),
+					flags.StringFlagS("destination", 'd', "", ""),
+					flags.BoolFlag("force", false, ""),
+					flags.BoolFlag("quiet", false, ""),
+					flags.StringFlag("name", "", "").AddSources(flags.Environ),
+				),
+				Run: func(*gommand.Context) error { return nil },
+			})
+
+			args := append([]string{strings.Fields(tt.usage)[0]}, tt.args...)
+			err := root.ExecuteArgs(context.Background(), args)
+			if tt.wantMsg == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var target gommand.ErrUsage
+			if !errors.As(err, &target) {
+				t.Fatalf("expected ErrUsage, got %T: %v", err, err)
+			}
+			if target.Usage != tt.wantUsage || target.Msg != tt.wantMsg || target.Position != tt.wantPos {
+				t.Errorf("got %q %q at %d, want %q %q at %d", target.Usage, target.Msg, target.Position, tt.wantUsage, tt.wantMsg, tt.wantPos)
+			}
+		})
+	}
+
+	t.Run("error message", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", EnforceUsage: true, SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{
+			Name: create,
+			FlagSet: flags.NewFlagSet().AddFlags(
+				flags.StringFlag("from-file", "", ""),
+				flags.StringFlag("from-gcs", "", ""),
+				flags.StringFlagS("destination", 'd', "", ""),
+			),
+			Run: func(*gommand.Context) error { return nil },
+		})
+
+		err := root.ExecuteArgs(context.Background(), []string{"create", "x"})
+		want := `gommand: mytool create: missing required flag in usage "{--from-file file | --from-gcs bucket}"`
+		if err == nil || err.Error() != want {
+			t.Errorf("got %v, want %s", err, want)
+		}
+	})
+
+	t.Run("not enforced by default", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{Name: "copy src [dest]", Run: func(*gommand.Context) error { return nil }})
+
+		if err := root.ExecuteArgs(context.Background(), []string{"copy", "a"}); err == nil {
+			t.Error("expected ArgsNone to reject args without EnforceUsage")
+		}
+	})
+
+	t.Run("derived from args", func(t *testing.T) {
+		root := &gommand.Command{Name: "mytool", EnforceUsage: true, SilenceHelp: true, SilenceError: true}
+		root.SubCommand(&gommand.Command{
+			Name: "get",
+			Args: []gommand.Arg{{Flag: flags.StringFlag("name", "", "")}},
+			Run:  func(*gommand.Context) error { return nil },
+		})
+		err := root.ExecuteArgs(context.Background(), []string{"get", "a", "b"})
+		var target gommand.ErrUsage
+		if !errors.As(err, &target) || target.Usage != "name" {
+			t.Errorf("expected ErrUsage for usage %q, got %v", "name", err)
+		}
+	})
+}
+
+func TestValidateUsage(t *testing.T) {
+	tests := []struct {
+		name    string
+		usage   string
+		enforce bool
+		want    string
+	}{
+		{name: "valid", usage: "create {--from-file file | --from-gcs bucket} [-d destination] file_name...", enforce: true},
+		{name: "unclosed", usage: "create [-d destination", want: `gommand: mytool create: invalid usage "[-d destination": unclosed "[" at offset 0`},
+		{name: "mismatched", usage: "create {a | b]", want: `gommand: mytool create: invalid usage "{a | b]": unexpected "]" at offset 6, expected "}" to close "{" at offset 0`},
+		{name: "stray closer", usage: "create a ] b", want: `gommand: mytool create: invalid usage "a ] b": unexpected "]" at offset 2`},
+		{name: "empty alternative", usage: "create {a | }", want: `gommand: mytool create: invalid usage "{a | }": empty alternative in "{" at offset 0`},
+		{name: "undefined flag", usage: "create --missing value", enforce: true, want: "gommand: mytool create: usage references undefined flag --missing"},
+		{name: "undefined flag not enforced", usage: "create --missing value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := &gommand.Command{Name: "mytool", EnforceUsage: tt.enforce}
+			root.SubCommand(&gommand.Command{
+				Name: tt.usage,
+				FlagSet: flags.NewFlagSet().AddFlags(
+					flags.StringFlag("from-file", "", ""),
+					flags.StringFlag("from-gcs", "", ""),
+					flags.StringFlagS("destination", 'd', "", ""),
+				),
+				Run: func(*gommand.Context) error { return nil },
+			})
+			err := root.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("got %v, want %s", err, tt.want)
+			}
+		})
+	}
+}
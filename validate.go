@@ -0,0 +1,153 @@
+package gommand
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/jimmykodes/gommand/flags"
+)
+
+// Validate walks the command tree from c and reports problems that would otherwise
+// silently change how arguments are parsed:
+//   - subcommands of the same command sharing a name or alias
+//   - flags in the same FlagSet sharing a name or shorthand
+//   - flags shadowing, or sharing a shorthand with a different flag, inherited from a PersistentFlagSet
+//   - flags named like the negated form of a negatable flag, ie: --no-cache alongside --cache
+//   - flags that clash with the reserved -h, --help and --version flags
+//   - subcommands in a Group that is not defined by their parent
+//   - Args that can not be parsed unambiguously, ie: a required arg after an optional one
+//   - usage descriptions in the Name of the command that can not be parsed, or, if EnforceUsage
+//     is set, that reference undefined flags
+//
+// Every conflict is reported as an ErrConflict or flags.ErrFlagConflict, joined
+// together with errors.Join. It is intended to be called from a unit test.
+func (c *Command) Validate() error {
+	var inherited []*flags.FlagSet
+	for p := c.parent; p != nil; p = p.parent {
+		inherited = append(inherited, p.PersistentFlagSet)
+	}
+	return c.validate(inherited)
+}
+
+func (c *Command) validate(inherited []*flags.FlagSet) error {
+	var errs []error
+	conflict := func(format string, a ...any) {
+		errs = append(errs, ErrConflict{CommandPath: c.path(), Msg: fmt.Sprintf(format, a...)})
+	}
+
+	for _, fs := range []*flags.FlagSet{c.FlagSet, c.PersistentFlagSet} {
+		if fs != nil {
+			errs = append(errs, fs.Validate())
+		}
+	}
+
+	// the command's own persistent flags are inherited by its local flags
+	parents := flags.NewFlagSet()
+	for _, fs := range inherited {
+		parents.AddFlagSet(fs)
+	}
+	for _, fs := range []*flags.FlagSet{c.PersistentFlagSet, c.FlagSet} {
+		if fs == nil {
+			continue
+		}
+		// FromName resolves --no-<name> to the negated form of a negatable flag, so
+		// look flags up by the names they were registered with instead
+		var (
+			own       = maps.Collect(flags.NewFlagGetter(fs).All())
+			ancestors = maps.Collect(flags.NewFlagGetter(parents).All())
+		)
+		for _, name := range slices.Sorted(maps.Keys(own)) {
+			f := own[name]
+			switch {
+			case name == "help" || name == "version":
+				conflict("flag --%s is reserved", name)
+			case f.Short() == 'h':
+				conflict("flag --%s uses reserved shorthand -h", name)
+			case f.Short() != 0:
+				if pf := parents.FromShort(f.Short()); pf != nil && pf.Name() != name {
+					conflict("flag --%s shorthand -%c conflicts with inherited flag --%s", name, f.Short(), pf.Name())
+				}
+			}
+			if pf, ok := ancestors[name]; ok && pf != f {
+				conflict("flag --%s shadows an inherited flag of the same name", name)
+			}
+			if base, ok := strings.CutPrefix(name, "no-"); ok {
+				if other := cmp.Or(own[base], ancestors[base]); other != nil && flags.Negatable(other) {
+					conflict("flag --%s conflicts with the negated form of flag --%s", name, base)
+				}
+			}
+			if _, ok := ancestors["no-"+name]; ok && flags.Negatable(f) {
+				conflict("the negated form of flag --%s conflicts with inherited flag --no-%s", name, name)
+			}
+		}
+		parents.AddFlagSet(fs)
+	}
+
+	argNames := make(map[string]bool)
+	for i, arg := range c.Args {
+		switch {
+		case arg.Flag == nil:
+			conflict("argument %d has no flag", i)
+			continue
+		case argNames[arg.Flag.Name()]:
+			conflict("argument %s is defined more than once", arg.Flag.Name())
+		case arg.Variadic && i != len(c.Args)-1:
+			conflict("variadic argument %s is not the last argument", arg.Flag.Name())
+		case arg.Variadic && !flags.IsSliceFlag(arg.Flag):
+			conflict("variadic argument %s is not a slice flag", arg.Flag.Name())
+		case !arg.Optional && i > 0 && c.Args[i-1].Optional:
+			conflict("required argument %s follows optional argument %s", arg.Flag.Name(), c.Args[i-1].Flag.Name())
+		}
+		argNames[arg.Flag.Name()] = true
+	}
+
+	// a usage can only be derived from args that all have a flag
+	if !slices.ContainsFunc(c.Args, func(a Arg) bool { return a.Flag == nil }) {
+		// parents now holds the command's own flags too
+		all := parents
+		seq, err := c.parseUsage(all)
+		switch {
+		case err != nil:
+			conflict("invalid usage %q: %v", c.usage(), err)
+		case c.enforcesUsage():
+			_ = walkUsageFlags(seq, func(e *usageElem) error {
+				if usageFlag(all, e.flag, e.short) == nil && e.flag != "help" && e.flag != "h" && e.flag != "version" {
+					conflict("usage references undefined flag %s", strings.Fields(e.text)[0])
+				}
+				return nil
+			})
+		}
+	}
+
+	seen := make(map[string]*Command)
+	for _, sub := range c.children {
+		if sub.Group != "" && !slices.ContainsFunc(c.Groups, func(g Group) bool { return g.ID == sub.Group }) {
+			conflict("subcommand %q is in undefined group %q", sub.name(), sub.Group)
+		}
+		names := append([]string{sub.name()}, sub.Aliases...)
+		names = append(names, slices.Sorted(maps.Keys(sub.DeprecatedAliases))...)
+		for _, name := range names {
+			if other, ok := seen[name]; ok && other != sub {
+				conflict("subcommands %q and %q are both called %q", other.name(), sub.name(), name)
+			}
+			seen[name] = sub
+		}
+		errs = append(errs, sub.validate(append(slices.Clip(inherited), c.PersistentFlagSet)))
+	}
+
+	return errors.Join(errs...)
+}
+
+// enforcesUsage reports whether EnforceUsage is set on the command or any of its parents
+func (c *Command) enforcesUsage() bool {
+	for p := c; p != nil; p = p.parent {
+		if p.EnforceUsage {
+			return true
+		}
+	}
+	return false
+}
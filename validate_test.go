@@ -0,0 +1,212 @@
+package gommand_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jimmykodes/gommand"
+	"github.com/jimmykodes/gommand/flags"
+)
+
+func TestValidate(t *testing.T) {
+	noop := func(*gommand.Context) error { return nil }
+
+	tests := []struct {
+		name  string
+		build func() *gommand.Command
+		want  []string
+	}{
+		{
+			name: "valid tree",
+			build: func() *gommand.Command {
+				pfs := flags.NewFlagSet()
+				pfs.BoolS("verbose", 'v', false, "")
+				fs := flags.NewFlagSet()
+				fs.StringS("output", 'o', "", "")
+				// the same flag can be added to both a persistent and a local flag set
+				fs.AddFlag(pfs.FromName("verbose"))
+
+				root := &gommand.Command{Name: "root", PersistentFlagSet: pfs}
+				root.SubCommand(
+					&gommand.Command{Name: "list", Aliases: []string{"ls"}, FlagSet: fs, Run: noop},
+					&gommand.Command{Name: "get", Run: noop},
+				)
+				return root
+			},
+		},
+		{
+			name: "subcommand name and alias collisions",
+			build: func() *gommand.Command {
+				root := &gommand.Command{Name: "root"}
+				root.SubCommand(
+					&gommand.Command{Name: "list", Aliases: []string{"ls"}, Run: noop},
+					&gommand.Command{Name: "ls", Run: noop},
+					&gommand.Command{Name: "list", Run: noop},
+				)
+				return root
+			},
+			want: []string{
+				`gommand: root: subcommands "list" and "ls" are both called "ls"`,
+				`gommand: root: subcommands "list" and "list" are both called "list"`,
+			},
+		},
+		{
+			name: "nested collisions",
+			build: func() *gommand.Command {
+				root := &gommand.Command{Name: "root"}
+				server := &gommand.Command{Name: "server"}
+				server.SubCommand(
+					&gommand.Command{Name: "run", Run: noop},
+					&gommand.Command{Name: "start", DeprecatedAliases: map[string]string{"run": "use run"}, Run: noop},
+				)
+				root.SubCommand(server)
+				return root
+			},
+			want: []string{`gommand: root server: subcommands "run" and "start" are both called "run"`},
+		},
+		{
+			name: "shorthand collision with inherited flag",
+			build: func() *gommand.Command {
+				pfs := flags.NewFlagSet()
+				pfs.BoolS("verbose", 'v', false, "")
+				fs := flags.NewFlagSet()
+				fs.StringS("version-file", 'v', "", "")
+
+				root := &gommand.Command{Name: "root", PersistentFlagSet: pfs}
+				mid := &gommand.Command{Name: "mid"}
+				mid.SubCommand(&gommand.Command{Name: "leaf", FlagSet: fs, Run: noop})
+				root.SubCommand(mid)
+				return root
+			},
+			want: []string{"gommand: root mid leaf: flag --version-file shorthand -v conflicts with inherited flag --verbose"},
+		},
+		{
+			name: "flag shadowing an inherited flag",
+			build: func() *gommand.Command {
+				pfs := flags.NewFlagSet()
+				pfs.BoolS("verbose", 'v', false, "")
+				fs := flags.NewFlagSet()
+				fs.BoolS("verbose", 'v', false, "")
+
+				root := &gommand.Command{Name: "root", PersistentFlagSet: pfs}
+				root.SubCommand(&gommand.Command{Name: "list", FlagSet: fs, Run: noop})
+				return root
+			},
+			want: []string{"gommand: root list: flag --verbose shadows an inherited flag of the same name"},
+		},
+		{
+			name: "negated name collisions",
+			build: func() *gommand.Command {
+				pfs := flags.NewFlagSet()
+				pfs.Bool("cache", true, "")
+				pfs.String("no-color", "", "")
+				fs := flags.NewFlagSet()
+				fs.Bool("no-cache", false, "")
+				fs.Bool("color", true, "")
+				fs.Bool("prompt", true, "")
+				fs.Bool("no-prompt", false, "")
+
+				root := &gommand.Command{Name: "root", PersistentFlagSet: pfs}
+				root.SubCommand(&gommand.Command{Name: "list", FlagSet: fs, Run: noop})
+				return root
+			},
+			want: []string{
+				"gommand: root list: the negated form of flag --color conflicts with inherited flag --no-color",
+				"gommand: root list: flag --no-cache conflicts with the negated form of flag --cache",
+				"gommand: root list: flag --no-prompt conflicts with the negated form of flag --prompt",
+			},
+		},
+		{
+			name: "collisions within a flag set",
+			build: func() *gommand.Command {
+				fs := flags.NewFlagSet()
+				fs.StringS("output", 'o', "", "")
+				fs.String("output", "", "")
+				fs.BoolS("overwrite", 'o', false, "")
+				return &gommand.Command{Name: "root", FlagSet: fs, Run: noop}
+			},
+			want: []string{
+				"gommand: flag --output defined more than once",
+				"gommand: flags --overwrite and --output share shorthand -o",
+			},
+		},
+		{
+			name: "reserved flags",
+			build: func() *gommand.Command {
+				fs := flags.NewFlagSet()
+				fs.Bool("help", false, "")
+				fs.Bool("version", false, "")
+				fs.BoolS("host", 'h', false, "")
+				return &gommand.Command{Name: "root", FlagSet: fs, Run: noop}
+			},
+			want: []string{
+				"gommand: root: flag --help is reserved",
+				"gommand: root: flag --host uses reserved shorthand -h",
+				"gommand: root: flag --version is reserved",
+			},
+		},
+		{
+			name: "args",
+			build: func() *gommand.Command {
+				return &gommand.Command{Name: "root", Run: noop, Args: []gommand.Arg{
+					{Flag: flags.StringFlag("opt", "", ""), Optional: true},
+					{Flag: flags.StringFlag("name", "", "")},
+					{Flag: flags.StringFlag("name", "", "")},
+					{Flag: flags.StringFlag("files", "", ""), Variadic: true},
+					{Flag: flags.StringSliceFlag("tags", nil, ""), Variadic: true},
+					{},
+				}}
+			},
+			want: []string{
+				"gommand: root: required argument name follows optional argument opt",
+				"gommand: root: argument name is defined more than once",
+				"gommand: root: variadic argument files is not the last argument",
+				"gommand: root: variadic argument tags is not the last argument",
+				"gommand: root: argument 5 has no flag",
+			},
+		},
+		{
+			name: "variadic arg type",
+			build: func() *gommand.Command {
+				return &gommand.Command{Name: "root", Run: noop, Args: []gommand.Arg{
+					{Flag: flags.StringFlag("name", "", "")},
+					{Flag: flags.IntFlag("count", 0, ""), Optional: true},
+					{Flag: flags.StringFlag("files", "", ""), Optional: true, Variadic: true},
+				}}
+			},
+			want: []string{"gommand: root: variadic argument files is not a slice flag"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.build().Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected error, got nil")
+			}
+			if got := strings.Split(err.Error(), "\n"); strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("got errors:\n%s\nwant:\n%s", err, strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+
+	t.Run("conflicts are typed", func(t *testing.T) {
+		root := &gommand.Command{Name: "root"}
+		root.SubCommand(&gommand.Command{Name: "a", Run: noop}, &gommand.Command{Name: "a", Run: noop})
+
+		var target gommand.ErrConflict
+		if err := root.Validate(); !errors.As(err, &target) {
+			t.Fatalf("expected ErrConflict, got %T: %v", err, err)
+		}
+		if target.CommandPath != "root" {
+			t.Errorf("CommandPath = %q, want %q", target.CommandPath, "root")
+		}
+	})
+}